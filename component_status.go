@@ -4,16 +4,32 @@ import "time"
 
 // HealthComponentStatus manages the current status of an application component.
 type HealthComponentStatus struct {
-	health      *Health
-	key         interface{}
-	healthy     bool
-	lastUpdated time.Time
+	health        *Health
+	key           interface{}
+	healthy       bool
+	flapping      bool
+	lastUpdated   time.Time
+	ttl           time.Duration
+	lastHeartbeat time.Time
+	heartbeats    chan struct{}
+	unregistered  chan struct{}
+	history       *healthHistory
+	transitions   []time.Time
 }
 
-func newHealthComponentStatus(health *Health, key interface{}) *HealthComponentStatus {
+func newHealthComponentStatus(health *Health, key interface{}, ttl time.Duration) *HealthComponentStatus {
+	var heartbeats chan struct{}
+	if ttl > 0 {
+		heartbeats = make(chan struct{}, 1)
+	}
+
 	return &HealthComponentStatus{
-		health: health,
-		key:    key,
+		health:       health,
+		key:          key,
+		ttl:          ttl,
+		heartbeats:   heartbeats,
+		unregistered: make(chan struct{}),
+		history:      newHealthHistory(health.historySize),
 	}
 }
 
@@ -22,7 +38,23 @@ func (s *HealthComponentStatus) Healthy() bool {
 	s.health.mu.Lock()
 	defer s.health.mu.Unlock()
 
-	return s.healthy
+	return s.reportedHealthy()
+}
+
+// History returns the most recent transitions of the component's underlying status,
+// ordered from oldest to newest. The number of transitions retained is bounded by the
+// history size of the health instance.
+func (s *HealthComponentStatus) History() []HealthChange {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	return s.history.values()
+}
+
+// reportedHealthy returns true if the component is healthy and is not flapping.
+// Callers MUST lock s.health.mu.
+func (s *HealthComponentStatus) reportedHealthy() bool {
+	return s.healthy && !s.flapping
 }
 
 // Update sets the current health status of the application component.
@@ -30,11 +62,114 @@ func (s *HealthComponentStatus) Update(healthy bool) {
 	s.health.mu.Lock()
 	defer s.health.mu.Unlock()
 
-	if s.healthy == healthy {
+	s.update(healthy)
+}
+
+// Heartbeat marks the application component as healthy. If the component was
+// registered with a TTL, the component will become unhealthy if another heartbeat
+// does not occur within the TTL. For components registered without a TTL, this
+// method is equivalent to calling Update(true).
+func (s *HealthComponentStatus) Heartbeat() {
+	s.health.mu.Lock()
+	s.lastHeartbeat = s.health.clock.Now()
+	s.update(true)
+	s.health.mu.Unlock()
+
+	if s.heartbeats == nil {
 		return
 	}
 
+	select {
+	case s.heartbeats <- struct{}{}:
+	default:
+	}
+}
+
+// expireHeartbeats marks the component as unhealthy each time the TTL elapses
+// without an intervening heartbeat.
+func (s *HealthComponentStatus) expireHeartbeats() {
+	for {
+		select {
+		case <-s.heartbeats:
+		case <-s.unregistered:
+			return
+		}
+
+		for expired := false; !expired; {
+			select {
+			case <-s.heartbeats:
+			case <-s.health.clock.After(s.untilExpiry()):
+				expired = true
+			case <-s.unregistered:
+				return
+			}
+		}
+
+		s.expire()
+	}
+}
+
+// untilExpiry returns the duration until the TTL elapses since the last heartbeat.
+func (s *HealthComponentStatus) untilExpiry() time.Duration {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	return s.health.clock.Until(s.lastHeartbeat.Add(s.ttl))
+}
+
+// expire marks the component as unhealthy if the TTL has elapsed since the last
+// heartbeat.
+func (s *HealthComponentStatus) expire() {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	if s.health.clock.Since(s.lastHeartbeat) >= s.ttl {
+		s.update(false)
+	}
+}
+
+// update sets the current health status of the application component and notifies
+// subscribers of a change. Callers MUST lock s.health.mu.
+func (s *HealthComponentStatus) update(healthy bool) {
+	if s.healthy == healthy || s.isUnregistered() {
+		return
+	}
+
+	now := s.health.clock.Now()
+	old := s.reportedHealthy()
+
+	s.history.add(HealthChange{
+		Key: s.key,
+		Old: s.healthy,
+		New: healthy,
+		At:  now,
+	})
+
 	s.healthy = healthy
-	s.lastUpdated = time.Now()
-	s.health.notify()
+	s.lastUpdated = now
+	s.detectFlapping(now)
+	s.notifyIfChanged(old, now)
+}
+
+// isUnregistered returns true if the component has been removed from its health instance.
+func (s *HealthComponentStatus) isUnregistered() bool {
+	select {
+	case <-s.unregistered:
+		return true
+	default:
+		return false
+	}
+}
+
+// notifyIfChanged notifies subscribers of the health instance if the reported status
+// of the component differs from the given previous value. Callers MUST lock s.health.mu.
+func (s *HealthComponentStatus) notifyIfChanged(old bool, now time.Time) {
+	if healthy := s.reportedHealthy(); healthy != old {
+		s.health.notify(HealthChange{
+			Key: s.key,
+			Old: old,
+			New: healthy,
+			At:  now,
+		})
+	}
 }
@@ -30,3 +30,30 @@ func (c *Container) Priorities() []int {
 	copy(priorities, c.priorities)
 	return priorities
 }
+
+// Get returns the meta value registered to the container with the given name. If more
+// than one meta value has the given name, the one with the lowest priority is returned.
+func (c *Container) Get(name string) (*Meta, bool) {
+	for _, priority := range c.priorities {
+		for _, meta := range c.meta[priority] {
+			if meta.Name() == name {
+				return meta, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Names returns a new slice of the names of the meta values registered to the container
+// ordered by priority, then by registration order.
+func (c *Container) Names() []string {
+	var names []string
+	for _, priority := range c.priorities {
+		for _, meta := range c.meta[priority] {
+			names = append(names, meta.Name())
+		}
+	}
+
+	return names
+}
@@ -7,6 +7,7 @@ import "sort"
 // container.
 type ContainerBuilder struct {
 	registrations []registration
+	strict        bool
 }
 
 // registration is a raw process value paired with the set of configuration
@@ -17,8 +18,13 @@ type registration struct {
 }
 
 // NewContainerBuilder creates an empty container builder.
-func NewContainerBuilder() *ContainerBuilder {
-	return &ContainerBuilder{}
+func NewContainerBuilder(configs ...ContainerBuilderConfigFunc) *ContainerBuilder {
+	b := &ContainerBuilder{}
+	for _, f := range configs {
+		f(b)
+	}
+
+	return b
 }
 
 // RegisterInitializer registers an initializer with the given configs.
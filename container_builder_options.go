@@ -0,0 +1,10 @@
+package process
+
+type ContainerBuilderConfigFunc func(*ContainerBuilder)
+
+// WithStrictValidation configures a container builder to additionally reject processes
+// that declare a hook method with an unexpected signature (e.g., Stop() without a context
+// parameter) or declare hook methods only on a pointer receiver while registered by value.
+func WithStrictValidation() ContainerBuilderConfigFunc {
+	return func(b *ContainerBuilder) { b.strict = true }
+}
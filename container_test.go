@@ -0,0 +1,26 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerGet(t *testing.T) {
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("a"), WithMetaPriority(1))
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("b"))
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("c"), WithMetaPriority(1))
+	container := builder.Build()
+
+	meta, ok := container.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, "a", meta.Name())
+	assert.Equal(t, 1, meta.Priority())
+
+	_, ok = container.Get("d")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"b", "a", "c"}, container.Names())
+}
@@ -1,10 +1,21 @@
 package process
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type healthKeyType struct{}
+type scopedHealthKeyType struct{}
+type listenersKeyType struct{}
+type loggerKeyType struct{}
+type shutdownTimeoutKeyType struct{}
 
 var healthKey = healthKeyType{}
+var scopedHealthKey = scopedHealthKeyType{}
+var listenersKey = listenersKeyType{}
+var loggerKey = loggerKeyType{}
+var shutdownTimeoutKey = shutdownTimeoutKeyType{}
 
 func ContextWithHealth(ctx context.Context, health *Health) context.Context {
 	return context.WithValue(ctx, healthKey, health)
@@ -16,3 +27,50 @@ func HealthFromContext(ctx context.Context) *Health {
 	}
 	return nil
 }
+
+func contextWithScopedHealth(ctx context.Context, health *ScopedHealth) context.Context {
+	return context.WithValue(ctx, scopedHealthKey, health)
+}
+
+func ScopedHealthFromContext(ctx context.Context) *ScopedHealth {
+	if v, ok := ctx.Value(scopedHealthKey).(*ScopedHealth); ok {
+		return v
+	}
+	return nil
+}
+
+func ContextWithListeners(ctx context.Context, listeners *Listeners) context.Context {
+	return context.WithValue(ctx, listenersKey, listeners)
+}
+
+func ListenersFromContext(ctx context.Context) *Listeners {
+	if v, ok := ctx.Value(listenersKey).(*Listeners); ok {
+		return v
+	}
+	return nil
+}
+
+func contextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+func LoggerFromContext(ctx context.Context) Logger {
+	if v, ok := ctx.Value(loggerKey).(Logger); ok {
+		return v
+	}
+	return nil
+}
+
+func contextWithShutdownTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, shutdownTimeoutKey, timeout)
+}
+
+// ShutdownTimeoutFromContext returns the time a process's Run method is given to return
+// once its context is canceled, or zero if the process may take an unbounded amount of
+// time to return.
+func ShutdownTimeoutFromContext(ctx context.Context) time.Duration {
+	if v, ok := ctx.Value(shutdownTimeoutKey).(time.Duration); ok {
+		return v
+	}
+	return 0
+}
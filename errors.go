@@ -3,6 +3,7 @@ package process
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // ErrUnexpectedReturn occurs when a process returns from Run before the process
@@ -18,6 +19,10 @@ var ErrStartupTimeout = errors.New("process did not become healthy within timeou
 // to Stop and a context cancellation within the configured timeout.
 var ErrShutdownTimeout = errors.New("process refusing to shut down; abandoning goroutine")
 
+// ErrWatchdogTimeout occurs when the health items associated with a process do not
+// report healthy within the configured watchdog timeout after becoming unhealthy.
+var ErrWatchdogTimeout = errors.New("process stalled; watchdog timeout elapsed")
+
 // ErrHealthCheckCanceled occurs when a process's initial health check is cancelled
 // due to another process exiting in a non-healthy way.
 var ErrHealthCheckCanceled = errors.New("health check canceled")
@@ -26,6 +31,57 @@ var ErrHealthCheckCanceled = errors.New("health check canceled")
 // with the name of previously registered health component.
 var ErrHealthComponentAlreadyRegistered = errors.New("health component already registered")
 
+// ErrHealthComponentNotRegistered occurs when a health component is unregistered
+// with a name that does not belong to a registered health component.
+var ErrHealthComponentNotRegistered = errors.New("health component not registered")
+
+// ErrHealthComponentInvalidTTL occurs when a health component is registered with a
+// non-positive TTL.
+var ErrHealthComponentInvalidTTL = errors.New("health component TTL must be positive")
+
+// ErrHealthChildAlreadyRegistered occurs when a child health instance is added with
+// the name of a previously added child health instance.
+var ErrHealthChildAlreadyRegistered = errors.New("health child already registered")
+
+// ErrHealthChildNotRegistered occurs when a child health instance is removed with a
+// name that does not belong to a previously added child health instance.
+var ErrHealthChildNotRegistered = errors.New("health child not registered")
+
+// ErrHealthChildCycle occurs when a health instance is added as a child of itself or
+// of one of its descendants.
+var ErrHealthChildCycle = errors.New("health child would create a cycle")
+
+// ErrListenerAlreadyRegistered occurs when a listener is registered with the name of a
+// previously registered listener.
+var ErrListenerAlreadyRegistered = errors.New("listener already registered")
+
+// ErrListenerNotInheritable occurs when a program is upgraded while a registered
+// listener does not expose its underlying file.
+var ErrListenerNotInheritable = errors.New("listener cannot be inherited")
+
+// ErrUpgradeInProgress occurs when an upgrade is requested while a previous upgrade
+// is in progress or has completed.
+var ErrUpgradeInProgress = errors.New("upgrade already in progress")
+
+// ErrUpgradeChildExited occurs when the program started by an upgrade exits before
+// reporting readiness.
+var ErrUpgradeChildExited = errors.New("upgraded program exited before becoming ready")
+
+// ErrUpgradeReadyTimeout occurs when the program started by an upgrade does not report
+// readiness within the configured timeout.
+var ErrUpgradeReadyTimeout = errors.New("upgraded program did not become ready within timeout")
+
+// ErrExecKilled occurs when the command run by an exec process does not exit within
+// the kill timeout after the process is stopped and is killed.
+var ErrExecKilled = errors.New("command did not exit after stop signal; killed")
+
+// ErrPIDFileLocked occurs when a PID file is locked by another instance of the program.
+var ErrPIDFileLocked = errors.New("pid file locked")
+
+// ErrPIDFileUnsupported occurs when a PID file is initialized on a platform that does
+// not support file locks.
+var ErrPIDFileUnsupported = errors.New("pid file locks are not supported on this platform")
+
 type opError struct {
 	source   error
 	metaName string
@@ -45,3 +101,56 @@ func (e opError) Error() string {
 func (e opError) Unwrap() error {
 	return e.source
 }
+
+// MultiError is a collection of errors that occurred during a single operation.
+type MultiError []error
+
+func (e MultiError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// Is returns true if any error in the collection matches the given target. Go versions
+// prior to 1.20 do not inspect the errors returned from Unwrap() []error.
+func (e MultiError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in the collection that matches the given target and, if one
+// is found, sets the target to that error value and returns true.
+func (e MultiError) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorOrNil returns nil if the given collection of errors is empty, the single error
+// if it contains one error, and the collection otherwise.
+func errorOrNil(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	return MultiError(errs)
+}
@@ -0,0 +1,24 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiErrorIsAs(t *testing.T) {
+	watchdogErr := &WatchdogError{MetaName: "test"}
+	err := fmt.Errorf("wrapped: %w", MultiError{testErr1, fmt.Errorf("reload: %w", watchdogErr)})
+
+	assert.True(t, MultiError{testErr1}.Is(testErr1))
+	assert.False(t, MultiError{testErr1}.Is(testErr2))
+	assert.True(t, errors.Is(err, ErrWatchdogTimeout))
+	assert.False(t, errors.Is(err, ErrShutdownTimeout))
+
+	var target *WatchdogError
+	require.True(t, MultiError{testErr1, watchdogErr}.As(&target))
+	assert.Equal(t, watchdogErr, target)
+}
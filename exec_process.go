@@ -0,0 +1,321 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+// defaultExecKillTimeout is the time a command is given to exit after the stop signal
+// before it is killed when the process has an unbounded shutdown timeout.
+const defaultExecKillTimeout = time.Second * 10
+
+// ExecProcess is a process that runs an external command. The command is started when
+// the process runs and is sent a stop signal (SIGTERM by default) when the process is
+// stopped or its context is canceled. A command that does not exit within the kill
+// timeout after the process's context is canceled is killed. By default, the kill timeout is derived from the
+// shutdown timeout of the process so that the command is killed before the process
+// runner gives up on it.
+//
+// Each line written to the command's standard output and standard error is forwarded
+// to the process's logger at the info and warning level, respectively. While the
+// command is running, the health component registered under HealthKey is healthy.
+type ExecProcess struct {
+	name        string
+	path        string
+	args        []string
+	env         []string
+	dir         string
+	stopSignal  os.Signal
+	killTimeout time.Duration
+	clock       glock.Clock
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	stopping  bool
+	signaled  bool
+	component *HealthComponentStatus
+}
+
+var _ Initializer = &ExecProcess{}
+var _ Runner = &ExecProcess{}
+var _ Stopper = &ExecProcess{}
+
+// NewExecProcess creates a process that runs the named program with the given arguments.
+// The program is resolved as by exec.Command.
+func NewExecProcess(path string, args []string, configs ...ExecConfigFunc) *ExecProcess {
+	p := &ExecProcess{
+		name:       filepath.Base(path),
+		path:       path,
+		args:       args,
+		stopSignal: syscall.SIGTERM,
+		clock:      defaultClock,
+	}
+
+	for _, f := range configs {
+		f(p)
+	}
+
+	return p
+}
+
+// execHealthKey is the key of the health component reflecting the liveness of the
+// command run by an exec process.
+type execHealthKey struct {
+	process *ExecProcess
+}
+
+func (k execHealthKey) String() string {
+	return fmt.Sprintf("%s command", k.process.name)
+}
+
+// HealthKey returns the key of the health component that is healthy while the command
+// is running. Pass this key to WithMetaHealthKey to wait for the command to start before
+// starting processes at higher priorities.
+func (p *ExecProcess) HealthKey() interface{} {
+	return execHealthKey{process: p}
+}
+
+// Init resolves the program and registers the process's health component with the
+// scoped health instance in the given context, if any.
+func (p *ExecProcess) Init(ctx context.Context) error {
+	path, err := exec.LookPath(p.path)
+	if err != nil {
+		return err
+	}
+	p.path = path
+
+	if health := ScopedHealthFromContext(ctx); health != nil {
+		component, err := health.Register(p.HealthKey())
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		p.component = component
+		p.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Run starts the command and blocks until it exits. A nil error is returned if the
+// command exits with status zero or exits after the process is stopped. Otherwise, an
+// ExecExitError describing the exit status is returned.
+func (p *ExecProcess) Run(ctx context.Context) error {
+	logger := LoggerFromContext(ctx)
+	if logger == nil {
+		logger = NilLogger
+	}
+
+	cmd := exec.Command(p.path, p.args...)
+	cmd.Dir = p.dir
+	if len(p.env) > 0 {
+		cmd.Env = append(os.Environ(), p.env...)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.stopping {
+		p.mu.Unlock()
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	p.cmd = cmd
+	p.signaled = false
+	p.mu.Unlock()
+
+	p.updateHealth(true)
+	defer p.updateHealth(false)
+
+	logger = logger.WithFields(LogFields{"command": p.name, "pid": cmd.Process.Pid})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); forwardLines(stdout, logger.Info) }()
+	go func() { defer wg.Done(); forwardLines(stderr, logger.Warning) }()
+
+	exited := make(chan error, 1)
+	go func() {
+		wg.Wait()
+		exited <- cmd.Wait()
+	}()
+
+	killed := false
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		// The context may be canceled without the process being stopped (e.g., by the
+		// watchdog); give the command a chance to exit gracefully before it is killed
+		p.mu.Lock()
+		_ = p.signalStop()
+		p.mu.Unlock()
+
+		killTimeout := p.killTimeoutFor(ctx)
+
+		select {
+		case err = <-exited:
+		case <-p.clock.After(killTimeout):
+			logger.Warning("%s did not exit within %s; killing", p.name, killTimeout)
+			_ = cmd.Process.Kill()
+			killed = true
+
+			// A descendant of the command may still hold the write end of its output
+			// pipes. Close the read ends so the forwarders do not block reaping.
+			_ = stdout.Close()
+			_ = stderr.Close()
+			err = <-exited
+		}
+	}
+
+	return p.exitError(cmd, err, killed)
+}
+
+// execKillTimeoutFraction is the fraction of the process's shutdown timeout the command
+// is given to exit before it is killed. The remainder is left to reap the command.
+const execKillTimeoutFraction = 0.9
+
+// killTimeoutFor returns the time the command is given to exit after the given context
+// is canceled. This is the configured kill timeout, capped so that the command is killed
+// before the shutdown timeout of the process elapses.
+func (p *ExecProcess) killTimeoutFor(ctx context.Context) time.Duration {
+	killTimeout := p.killTimeout
+
+	if shutdownTimeout := ShutdownTimeoutFromContext(ctx); shutdownTimeout > 0 {
+		limit := time.Duration(float64(shutdownTimeout) * execKillTimeoutFraction)
+		if killTimeout <= 0 || killTimeout > limit {
+			killTimeout = limit
+		}
+	}
+
+	if killTimeout <= 0 {
+		killTimeout = defaultExecKillTimeout
+	}
+
+	return killTimeout
+}
+
+// exitError converts the error returned from waiting on the given command into the
+// error returned from Run.
+func (p *ExecProcess) exitError(cmd *exec.Cmd, err error, killed bool) error {
+	if killed {
+		return ErrExecKilled
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return err
+	}
+
+	p.mu.Lock()
+	stopping := p.stopping
+	p.mu.Unlock()
+
+	if err == nil || stopping {
+		return nil
+	}
+
+	return &ExecExitError{
+		Name:     p.name,
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Err:      exitErr,
+	}
+}
+
+// Stop sends the stop signal to the running command. If the signal cannot be delivered
+// (e.g., on platforms without signals), the command is killed.
+func (p *ExecProcess) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopping = true
+	return p.signalStop()
+}
+
+// signalStop sends the stop signal to the running command unless it has already been
+// sent. If the signal cannot be delivered, the command is killed. Callers MUST lock p.mu.
+func (p *ExecProcess) signalStop() error {
+	if p.cmd == nil || p.signaled {
+		return nil
+	}
+	p.signaled = true
+
+	if err := p.cmd.Process.Signal(p.stopSignal); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return p.cmd.Process.Kill()
+	}
+
+	return nil
+}
+
+// updateHealth sets the status of the process's health component, if registered.
+func (p *ExecProcess) updateHealth(healthy bool) {
+	p.mu.Lock()
+	component := p.component
+	p.mu.Unlock()
+
+	if component != nil {
+		component.Update(healthy)
+	}
+}
+
+// forwardLines invokes the given function with each line read from the given reader.
+func forwardLines(r io.Reader, log func(string, ...interface{})) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if line[len(line)-1] == '\n' {
+				line = line[:len(line)-1]
+			}
+
+			log("%s", line)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ExecExitError occurs when the command run by an exec process exits with a non-zero
+// status before the process is stopped.
+type ExecExitError struct {
+	// Name is the name of the command.
+	Name string
+
+	// ExitCode is the exit status of the command, or -1 if it was terminated by a
+	// signal.
+	ExitCode int
+
+	// Err is the error returned from waiting on the command.
+	Err error
+}
+
+func (e *ExecExitError) Error() string {
+	return fmt.Sprintf("%s exited with status %d", e.Name, e.ExitCode)
+}
+
+func (e *ExecExitError) Unwrap() error {
+	return e.Err
+}
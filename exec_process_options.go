@@ -0,0 +1,46 @@
+package process
+
+import (
+	"os"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+type ExecConfigFunc func(*ExecProcess)
+
+// WithExecName sets the name of the command used in log fields, errors, and the key of
+// its health component. The default name is the base name of the program.
+func WithExecName(name string) ExecConfigFunc {
+	return func(p *ExecProcess) { p.name = name }
+}
+
+// WithExecEnv adds the given KEY=value pairs to the environment of the command. The
+// command inherits the environment of the current program.
+func WithExecEnv(env ...string) ExecConfigFunc {
+	return func(p *ExecProcess) { p.env = append(p.env, env...) }
+}
+
+// WithExecDir sets the working directory of the command.
+func WithExecDir(dir string) ExecConfigFunc {
+	return func(p *ExecProcess) { p.dir = dir }
+}
+
+// WithExecStopSignal sets the signal sent to the command when the process is stopped.
+// The default signal is SIGTERM.
+func WithExecStopSignal(signal os.Signal) ExecConfigFunc {
+	return func(p *ExecProcess) { p.stopSignal = signal }
+}
+
+// WithExecKillTimeout sets the maximum time the command may continue to run after the
+// process's context is canceled before it is killed. If the process has a shutdown
+// timeout, the command is killed no later than 90% of it. The default behavior is to
+// derive the kill timeout from the shutdown timeout, or to use ten seconds if the
+// shutdown timeout is unbounded.
+func WithExecKillTimeout(timeout time.Duration) ExecConfigFunc {
+	return func(p *ExecProcess) { p.killTimeout = timeout }
+}
+
+func withExecClock(clock glock.Clock) ExecConfigFunc {
+	return func(p *ExecProcess) { p.clock = clock }
+}
@@ -0,0 +1,146 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecProcessLogs(t *testing.T) {
+	logger := &testLogger{}
+	process := NewExecProcess("sh", []string{"-c", "echo out; echo err >&2"})
+	meta := newMeta(process, WithMetaName("sidecar"), WithMetaLogger(logger), WithMetaLogLevel(LogLevelInfo), WithEarlyExit(true))
+
+	require.Nil(t, meta.Init(context.Background()))
+	require.Nil(t, meta.Run(context.Background()))
+	assert.ElementsMatch(t, []string{"INFO: out", "WARN: err"}, logger.snapshot())
+}
+
+func TestExecProcessExitError(t *testing.T) {
+	process := NewExecProcess("sh", []string{"-c", "exit 3"}, WithExecName("sidecar"))
+	require.Nil(t, process.Init(context.Background()))
+
+	err := process.Run(context.Background())
+	assert.EqualError(t, err, "sidecar exited with status 3")
+
+	var exitErr *ExecExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode)
+}
+
+func TestExecProcessStop(t *testing.T) {
+	health := NewHealth()
+	process := NewExecProcess("sleep", []string{"10"})
+
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(process, WithMetaName("sidecar"), WithMetaHealthKey(process.HealthKey()))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health))
+	require.Eventually(t, func() bool {
+		_, ok := state.StartupReport()
+		return ok
+	}, time.Second*5, time.Millisecond)
+
+	component, ok := health.Get(process.HealthKey())
+	require.True(t, ok)
+	assert.True(t, component.Healthy())
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+}
+
+func TestExecProcessKill(t *testing.T) {
+	clock := glock.NewMockClock()
+	logger := &testLogger{}
+	process := NewExecProcess("sh", []string{"-c", `trap "" TERM; echo ready; while true; do sleep 0.01; done`}, WithExecKillTimeout(time.Second*5), withExecClock(clock))
+	require.Nil(t, process.Init(context.Background()))
+
+	ctx, cancel := context.WithCancel(contextWithLogger(context.Background(), logger))
+	defer cancel()
+
+	result := runAsync(ctx, process.Run)
+	require.Eventually(t, func() bool {
+		messages := logger.snapshot()
+		return len(messages) > 0 && messages[0] == "INFO: ready"
+	}, time.Second*5, time.Millisecond)
+
+	require.Nil(t, process.Stop(context.Background()))
+	cancel()
+
+	clock.BlockingAdvance(time.Second * 5)
+	assert.Equal(t, ErrExecKilled, <-result)
+}
+
+func TestExecProcessContextCanceled(t *testing.T) {
+	clock := glock.NewMockClock()
+	logger := &testLogger{}
+	process := NewExecProcess("sh", []string{"-c", `trap "echo stopped; exit 0" TERM; echo ready; while true; do sleep 0.01; done`}, WithExecKillTimeout(time.Second*5), withExecClock(clock))
+	require.Nil(t, process.Init(context.Background()))
+
+	ctx, cancel := context.WithCancel(contextWithLogger(context.Background(), logger))
+	defer cancel()
+
+	result := runAsync(ctx, process.Run)
+	require.Eventually(t, func() bool {
+		messages := logger.snapshot()
+		return len(messages) > 0 && messages[0] == "INFO: ready"
+	}, time.Second*5, time.Millisecond)
+
+	// The command receives the stop signal even though the process was not stopped
+	cancel()
+
+	select {
+	case err := <-result:
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"INFO: ready", "INFO: stopped"}, logger.snapshot())
+	case <-time.After(time.Second * 5):
+		t.Fatalf("run did not return after the context was canceled")
+	}
+}
+
+func TestExecProcessKillWithDescendantHoldingOutput(t *testing.T) {
+	clock := glock.NewMockClock()
+	logger := &testLogger{}
+	process := NewExecProcess("sh", []string{"-c", `trap "" TERM; echo ready; sleep 3; :`}, WithExecKillTimeout(time.Second*5), withExecClock(clock))
+	require.Nil(t, process.Init(context.Background()))
+
+	ctx, cancel := context.WithCancel(contextWithLogger(context.Background(), logger))
+	defer cancel()
+
+	result := runAsync(ctx, process.Run)
+	require.Eventually(t, func() bool {
+		messages := logger.snapshot()
+		return len(messages) > 0 && messages[0] == "INFO: ready"
+	}, time.Second*5, time.Millisecond)
+
+	require.Nil(t, process.Stop(context.Background()))
+	cancel()
+	clock.BlockingAdvance(time.Second * 5)
+
+	select {
+	case err := <-result:
+		assert.Equal(t, ErrExecKilled, err)
+	case <-time.After(time.Second):
+		t.Fatalf("run did not return after the command was killed")
+	}
+}
+
+func TestExecProcessKillTimeout(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, defaultExecKillTimeout, NewExecProcess("sh", nil).killTimeoutFor(ctx))
+	assert.Equal(t, time.Second*5, NewExecProcess("sh", nil, WithExecKillTimeout(time.Second*5)).killTimeoutFor(ctx))
+
+	// The kill timeout is derived from, and capped by, the shutdown timeout of the process
+	ctx = contextWithShutdownTimeout(ctx, time.Second*10)
+	assert.Equal(t, time.Second*9, NewExecProcess("sh", nil).killTimeoutFor(ctx))
+	assert.Equal(t, time.Second*5, NewExecProcess("sh", nil, WithExecKillTimeout(time.Second*5)).killTimeoutFor(ctx))
+	assert.Equal(t, time.Second*9, NewExecProcess("sh", nil, WithExecKillTimeout(time.Second*20)).killTimeoutFor(ctx))
+}
@@ -3,30 +3,55 @@ package process
 import (
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
 )
 
 // Health is an aggregate container reporting the current health status of
 // individual application components.
 type Health struct {
-	mu          sync.Mutex
-	components  map[interface{}]*HealthComponentStatus
-	subscribers []chan<- struct{}
+	mu                 sync.Mutex
+	clock              glock.Clock
+	historySize        int
+	flapWindow         time.Duration
+	flapMaxTransitions int
+	components         map[interface{}]*HealthComponentStatus
+	subscribers        []chan<- struct{}
+	changeSubscribers  []*changeSubscriber
+	children           []healthChild
 }
 
 // NewHealth creates an empty Health instance.
-func NewHealth() *Health {
-	return &Health{
-		components: map[interface{}]*HealthComponentStatus{},
+func NewHealth(configs ...HealthConfigFunc) *Health {
+	h := &Health{
+		clock:       defaultClock,
+		historySize: defaultHealthHistorySize,
+		components:  map[interface{}]*HealthComponentStatus{},
+	}
+
+	for _, f := range configs {
+		f(h)
 	}
+
+	return h
 }
 
-// Healthy returns true if all registered components are healthy.
+// Healthy returns true if all registered components and child health instances
+// are healthy.
 func (h *Health) Healthy() bool {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	for _, component := range h.components {
-		if !component.healthy {
+		if !component.reportedHealthy() {
+			h.mu.Unlock()
+			return false
+		}
+	}
+	children := h.childList()
+	h.mu.Unlock()
+
+	for _, child := range children {
+		if !child.health.Healthy() {
 			return false
 		}
 	}
@@ -49,7 +74,11 @@ func (h *Health) Subscribe() (<-chan struct{}, func()) {
 
 	ch := make(chan struct{}, 1)
 	ch <- struct{}{}
-	h.subscribers = append(h.subscribers, ch)
+	if n < len(h.subscribers) {
+		h.subscribers[n] = ch
+	} else {
+		h.subscribers = append(h.subscribers, ch)
+	}
 
 	unsubscribe := func() {
 		h.mu.Lock()
@@ -61,8 +90,18 @@ func (h *Health) Subscribe() (<-chan struct{}, func()) {
 	return ch, func() { once.Do(unsubscribe) }
 }
 
-// Get returns the component status value registered to the given key.
+// Get returns the component status value registered to the given key. Components
+// registered to a child health instance are addressed by a ChildHealthKey.
 func (h *Health) Get(key interface{}) (*HealthComponentStatus, bool) {
+	if childKey, ok := key.(ChildHealthKey); ok {
+		child, ok := h.child(childKey.Name)
+		if !ok {
+			return nil, false
+		}
+
+		return child.Get(childKey.Key)
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -70,18 +109,35 @@ func (h *Health) Get(key interface{}) (*HealthComponentStatus, bool) {
 	return component, ok
 }
 
+// Keys returns the keys of all registered components, including the components of
+// child health instances addressed by a ChildHealthKey.
+func (h *Health) Keys() []interface{} {
+	h.mu.Lock()
+	keys := make([]interface{}, 0, len(h.components))
+	for key := range h.components {
+		keys = append(keys, key)
+	}
+	children := h.childList()
+	h.mu.Unlock()
+
+	for _, child := range children {
+		for _, key := range child.health.Keys() {
+			keys = append(keys, ChildHealthKey{Name: child.name, Key: key})
+		}
+	}
+
+	return keys
+}
+
 // GetAll returns the component status values registered to the given keys.
 func (h *Health) GetAll(keys ...interface{}) ([]*HealthComponentStatus, error) {
 	if len(keys) == 0 {
 		return nil, nil
 	}
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	components := make([]*HealthComponentStatus, 0, len(keys))
 	for _, key := range keys {
-		component, ok := h.components[key]
+		component, ok := h.Get(key)
 		if !ok {
 			return nil, fmt.Errorf("health component %q not registered", key)
 		}
@@ -95,6 +151,63 @@ func (h *Health) GetAll(keys ...interface{}) ([]*HealthComponentStatus, error) {
 // Register creates and returns a new component status value for the given key.
 // It an error to register the same key twice.
 func (h *Health) Register(key interface{}) (*HealthComponentStatus, error) {
+	return h.register(key, 0)
+}
+
+// RegisterWithTTL creates and returns a new component status value for the given
+// key. The component becomes healthy on each call to its Heartbeat method and
+// automatically becomes unhealthy if the next heartbeat does not occur within the
+// given TTL. It an error to register the same key twice or to supply a non-positive TTL.
+func (h *Health) RegisterWithTTL(key interface{}, ttl time.Duration) (*HealthComponentStatus, error) {
+	if ttl <= 0 {
+		return nil, ErrHealthComponentInvalidTTL
+	}
+
+	return h.register(key, ttl)
+}
+
+// Unregister removes the component status value registered to the given key. The
+// removed value no longer contributes to the health of this instance and further
+// updates to it are ignored. If the removed component was healthy, subscribers watching
+// its key receive a change to unhealthy. Components registered to a child health instance
+// are addressed by a ChildHealthKey.
+func (h *Health) Unregister(key interface{}) error {
+	if childKey, ok := key.(ChildHealthKey); ok {
+		child, ok := h.child(childKey.Name)
+		if !ok {
+			return ErrHealthComponentNotRegistered
+		}
+
+		return child.Unregister(childKey.Key)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	component, ok := h.components[key]
+	if !ok {
+		return ErrHealthComponentNotRegistered
+	}
+
+	wasHealthy := component.reportedHealthy()
+	delete(h.components, key)
+	close(component.unregistered)
+
+	if !wasHealthy {
+		h.notify()
+		return nil
+	}
+
+	h.notify(HealthChange{
+		Key: key,
+		Old: true,
+		New: false,
+		At:  h.clock.Now(),
+	})
+	return nil
+}
+
+func (h *Health) register(key interface{}, ttl time.Duration) (*HealthComponentStatus, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -102,14 +215,21 @@ func (h *Health) Register(key interface{}) (*HealthComponentStatus, error) {
 		return nil, ErrHealthComponentAlreadyRegistered
 	}
 
-	component := newHealthComponentStatus(h, key)
+	component := newHealthComponentStatus(h, key, ttl)
 	h.components[key] = component
 	h.notify()
+
+	if ttl > 0 {
+		go component.expireHeartbeats()
+	}
+
 	return component, nil
 }
 
-// notify writes a signal to all subscribed channels. Callers MUST lock b.mu.
-func (h *Health) notify() {
+// notify writes a signal to all subscribed channels and enqueues the given changes
+// for delivery to the change subscribers watching the changed keys. Callers MUST
+// lock h.mu.
+func (h *Health) notify(changes ...HealthChange) {
 	for _, subscriber := range h.subscribers {
 		if subscriber == nil {
 			continue
@@ -120,4 +240,12 @@ func (h *Health) notify() {
 		default:
 		}
 	}
+
+	for _, change := range changes {
+		for _, subscriber := range h.changeSubscribers {
+			if subscriber != nil && subscriber.watches(change.Key) {
+				subscriber.enqueue(change)
+			}
+		}
+	}
 }
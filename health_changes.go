@@ -0,0 +1,127 @@
+package process
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthChange describes a transition in the health status of a single component.
+type HealthChange struct {
+	Key interface{}
+	Old bool
+	New bool
+	At  time.Time
+}
+
+// SubscribeChanges returns a channel that receives a value describing each change in
+// the status of a component registered to one of the given keys. If no keys are given,
+// then changes to all components are delivered. Changes are delivered in order and are
+// buffered for slow readers. This method also returns a cancellation function that should
+// be called once the user wishes to unsubscribe. The returned channel is closed once the
+// cancellation function is called.
+func (h *Health) SubscribeChanges(keys ...interface{}) (<-chan HealthChange, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for n < len(h.changeSubscribers) && h.changeSubscribers[n] != nil {
+		n++
+	}
+
+	subscriber := newChangeSubscriber(keys)
+	if n < len(h.changeSubscribers) {
+		h.changeSubscribers[n] = subscriber
+	} else {
+		h.changeSubscribers = append(h.changeSubscribers, subscriber)
+	}
+
+	go subscriber.deliver()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		h.changeSubscribers[n] = nil
+		h.mu.Unlock()
+
+		close(subscriber.done)
+	}
+
+	var once sync.Once
+	return subscriber.ch, func() { once.Do(unsubscribe) }
+}
+
+// changeSubscriber buffers changes to a set of watched components for delivery
+// to a single reader.
+type changeSubscriber struct {
+	keys    map[interface{}]struct{}
+	mu      sync.Mutex
+	pending []HealthChange
+	signal  chan struct{}
+	ch      chan HealthChange
+	done    chan struct{}
+}
+
+func newChangeSubscriber(keys []interface{}) *changeSubscriber {
+	var keyMap map[interface{}]struct{}
+	if len(keys) > 0 {
+		keyMap = make(map[interface{}]struct{}, len(keys))
+		for _, key := range keys {
+			keyMap[key] = struct{}{}
+		}
+	}
+
+	return &changeSubscriber{
+		keys:   keyMap,
+		signal: make(chan struct{}, 1),
+		ch:     make(chan HealthChange),
+		done:   make(chan struct{}),
+	}
+}
+
+// watches returns true if changes to the given key should be delivered to this subscriber.
+func (s *changeSubscriber) watches(key interface{}) bool {
+	if s.keys == nil {
+		return true
+	}
+
+	_, ok := s.keys[key]
+	return ok
+}
+
+// enqueue adds the given change to the subscriber's buffer. This method does not block.
+func (s *changeSubscriber) enqueue(change HealthChange) {
+	s.mu.Lock()
+	s.pending = append(s.pending, change)
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// deliver writes buffered changes to the subscriber's channel in order until the
+// subscriber is cancelled. The subscriber's channel is closed on exit.
+func (s *changeSubscriber) deliver() {
+	defer close(s.ch)
+
+	for {
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+
+		for _, change := range pending {
+			select {
+			case s.ch <- change:
+			case <-s.done:
+				return
+			}
+		}
+
+		select {
+		case <-s.signal:
+		case <-s.done:
+			return
+		}
+	}
+}
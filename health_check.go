@@ -0,0 +1,197 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// healthCheckKey is the key of the health component registered on behalf of a
+// process that implements HealthChecker.
+type healthCheckKey struct {
+	meta *Meta
+}
+
+func (k healthCheckKey) String() string {
+	return fmt.Sprintf("%s health check", k.meta.Name())
+}
+
+// healthChecker returns the wrapped value as a HealthChecker. Active health checks
+// are only performed for values that are also runners.
+func (m *Meta) healthChecker() (HealthChecker, bool) {
+	if _, ok := m.wrapped.(Runner); !ok {
+		return nil, false
+	}
+
+	checker, ok := m.wrapped.(HealthChecker)
+	return checker, ok
+}
+
+// registerHealthCheck registers an initially unhealthy health component that reflects
+// the result of the wrapped value's CheckHealth method. The component is registered to
+// the health instance of the machine running this process, as the machine waits on it
+// during startup. This method no-ops if the wrapped value does not implement HealthChecker.
+func (m *Meta) registerHealthCheck() error {
+	if _, ok := m.healthChecker(); !ok {
+		return nil
+	}
+
+	component, err := m.machineHealth.Register(healthCheckKey{meta: m})
+	if err != nil {
+		return err
+	}
+
+	m.healthCheckComponent = component
+	return nil
+}
+
+// healthSources groups the health keys of this process by the health instance to which
+// the associated components are registered.
+func (m *Meta) healthSources() map[*Health][]interface{} {
+	sources := map[*Health][]interface{}{}
+	for _, key := range m.options.healthKeys {
+		health := m.options.health
+		if _, ok := key.(healthCheckKey); ok {
+			health = m.machineHealth.Health()
+		}
+
+		sources[health] = append(sources[health], key)
+	}
+
+	return sources
+}
+
+// healthComponents returns the health components of this process.
+func (m *Meta) healthComponents() ([]*HealthComponentStatus, error) {
+	var components []*HealthComponentStatus
+	for health, keys := range m.healthSources() {
+		healthComponents, err := health.GetAll(keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		components = append(components, healthComponents...)
+	}
+
+	return components, nil
+}
+
+// subscribeHealthChanges returns a channel that receives a value whenever the status of
+// a health component of this process may have changed, and a function that cancels the
+// subscription.
+func (m *Meta) subscribeHealthChanges() (<-chan struct{}, func()) {
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	var cancels []func()
+	for health, keys := range m.healthSources() {
+		ch, cancel := health.SubscribeChanges(keys...)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			for {
+				select {
+				case _, ok := <-ch:
+					if !ok {
+						return
+					}
+
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	cancel := func() {
+		close(done)
+
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+
+	return changed, cancel
+}
+
+// healthCheckInterval returns the configured health check interval, or the default
+// interval if the configured value is not positive.
+func (m *Meta) healthCheckInterval() time.Duration {
+	if m.options.healthCheckInterval <= 0 {
+		return defaultHealthCheckInterval
+	}
+
+	return m.options.healthCheckInterval
+}
+
+// runHealthChecks invokes the given checker's CheckHealth method immediately and then on
+// every tick of the configured health check interval. The health component registered in
+// registerHealthCheck is updated once the configured number of consecutive failures or
+// successes have been observed. This method blocks until the given context is canceled.
+func (m *Meta) runHealthChecks(ctx context.Context, checker HealthChecker) {
+	ticker := m.options.healthCheckClock.NewTicker(m.healthCheckInterval())
+	defer ticker.Stop()
+
+	failures := 0
+	successes := 0
+
+	for {
+		if err := m.checkHealth(ctx, checker); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			m.logger.Warning("%s", err)
+
+			successes = 0
+			if failures++; failures >= m.options.healthCheckFailureThreshold {
+				m.healthCheckComponent.Update(false)
+			}
+		} else {
+			failures = 0
+			if successes++; successes >= m.options.healthCheckSuccessThreshold {
+				m.healthCheckComponent.Update(true)
+			}
+		}
+
+		select {
+		case <-ticker.Chan():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHealth invokes the given checker's CheckHealth method. A timeout error will be
+// returned if the invocation does not unblock within the configured health check timeout.
+func (m *Meta) checkHealth(ctx context.Context, checker HealthChecker) error {
+	ctx, cancel := context.WithCancel(m.options.contextFilter(ctx))
+	defer cancel()
+
+	select {
+	case err := <-toStreamErrorFunc(checker.CheckHealth)(ctx):
+		if err != nil {
+			return &opError{
+				source:   err,
+				metaName: m.Name(),
+				opName:   "health check",
+				message:  "failed",
+			}
+		}
+
+		return nil
+
+	case <-afterZeroUnbounded(m.options.healthCheckClock, m.options.healthCheckTimeout):
+		return &opError{
+			source:   nil,
+			metaName: m.Name(),
+			opName:   "health check",
+			message:  "timeout",
+		}
+	}
+}
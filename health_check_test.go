@@ -0,0 +1,137 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type healthCheckingProcess struct {
+	*MockMaximumProcess
+	results chan error
+}
+
+func newHealthCheckingProcess() *healthCheckingProcess {
+	wrapped := NewMockMaximumProcess()
+	runHook, _ := newSingalingSingleErrorFunc()
+	wrapped.RunFunc.SetDefaultHook(runHook)
+
+	return &healthCheckingProcess{
+		MockMaximumProcess: wrapped,
+		results:            make(chan error),
+	}
+}
+
+func (p *healthCheckingProcess) CheckHealth(ctx context.Context) error {
+	select {
+	case err := <-p.results:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestMetaHealthCheck(t *testing.T) {
+	health := NewHealth()
+	clock := glock.NewMockClock()
+	wrapped := newHealthCheckingProcess()
+	meta := newMeta(
+		wrapped,
+		WithMetaName("test-service"),
+		WithMetaHealth(health),
+		WithMetaHealthCheckInterval(time.Second),
+		WithMetaHealthCheckFailureThreshold(2),
+		WithMetaHealthCheckSuccessThreshold(1),
+		withMetaHealthCheckClock(clock),
+	)
+	require.Nil(t, meta.Init(context.Background()))
+
+	components, err := health.GetAll(meta.options.healthKeys...)
+	require.Nil(t, err)
+	require.Len(t, components, 1)
+	assert.False(t, components[0].Healthy())
+
+	results := runAsync(context.Background(), meta.Run)
+
+	wrapped.results <- nil
+	assert.Eventually(t, components[0].Healthy, time.Second, time.Millisecond)
+
+	clock.Advance(time.Second)
+	wrapped.results <- testErr1
+	clock.Advance(time.Second)
+	wrapped.results <- testErr1
+	assert.Eventually(t, func() bool { return !components[0].Healthy() }, time.Second, time.Millisecond)
+
+	clock.Advance(time.Second)
+	wrapped.results <- nil
+	assert.Eventually(t, components[0].Healthy, time.Second, time.Millisecond)
+
+	assert.Nil(t, meta.Stop(context.Background()))
+	assertChannelContents(t, readErrorChannel(results), seq(nil))
+}
+
+func TestMetaHealthCheckTimeout(t *testing.T) {
+	health := NewHealth()
+	clock := glock.NewMockClock()
+	wrapped := newHealthCheckingProcess()
+	meta := newMeta(
+		wrapped,
+		WithMetaName("test-service"),
+		WithMetaHealth(health),
+		WithMetaHealthCheckInterval(time.Minute),
+		WithMetaHealthCheckTimeout(time.Second*5),
+		withMetaHealthCheckClock(clock),
+	)
+	require.Nil(t, meta.Init(context.Background()))
+
+	component, ok := health.Get(healthCheckKey{meta: meta})
+	require.True(t, ok)
+
+	results := runAsync(context.Background(), meta.Run)
+
+	wrapped.results <- nil
+	assert.Eventually(t, component.Healthy, time.Second, time.Millisecond)
+
+	clock.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		// The timeout is registered asynchronously with the invocation of the
+		// health check; advance until the check has been abandoned.
+		clock.Advance(time.Second * 5)
+		return !component.Healthy()
+	}, time.Second, time.Millisecond)
+
+	assert.Nil(t, meta.Stop(context.Background()))
+	assertChannelContents(t, readErrorChannel(results), seq(nil))
+}
+
+func TestMetaHealthCheckIntervalNonPositive(t *testing.T) {
+	assert.Equal(t, time.Second, newMeta(newHealthCheckingProcess(), WithMetaHealthCheckInterval(time.Second)).healthCheckInterval())
+	assert.Equal(t, defaultHealthCheckInterval, newMeta(newHealthCheckingProcess(), WithMetaHealthCheckInterval(0)).healthCheckInterval())
+	assert.Equal(t, defaultHealthCheckInterval, newMeta(newHealthCheckingProcess(), WithMetaHealthCheckInterval(-time.Second)).healthCheckInterval())
+	assert.Equal(t, defaultHealthCheckInterval, newMeta(newHealthCheckingProcess(), WithMetaHealthCheckInterval(0)).Options().HealthCheckInterval)
+}
+
+func TestRunHealthCheckWithoutMetaHealth(t *testing.T) {
+	health := NewHealth()
+	builder := NewContainerBuilder()
+	process := newHealthCheckingProcess()
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	state := Run(context.Background(), builder.Build(), WithHealth(health))
+	process.results <- nil
+
+	require.Eventually(t, func() bool {
+		_, ok := state.StartupReport()
+		return ok
+	}, time.Second, time.Millisecond)
+	require.Len(t, health.Keys(), 1)
+	assert.True(t, health.Healthy())
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+	assert.Empty(t, health.Keys())
+}
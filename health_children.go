@@ -0,0 +1,164 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ChildHealthKey addresses a component registered to a child health instance from
+// its parent. Keys of components registered to deeper descendants are formed by
+// nesting child health keys.
+type ChildHealthKey struct {
+	Name string
+	Key  interface{}
+}
+
+func (k ChildHealthKey) String() string {
+	return fmt.Sprintf("%s/%v", k.Name, k.Key)
+}
+
+// healthChild is a named child health instance.
+type healthChild struct {
+	name   string
+	health *Health
+	cancel func()
+}
+
+// healthChildrenMu serializes modifications of the child relationships between health
+// instances so that two concurrent additions cannot together form a cycle.
+var healthChildrenMu sync.Mutex
+
+// AddChild includes the given health instance into this health instance under the
+// given name. A parent is healthy only if all of its children are healthy, and the
+// components of a child are available from the parent via a ChildHealthKey. Changes
+// to a child are propagated to the subscribers of its parent until the child is removed.
+// It is an error to add a child with the same name twice, or to add a health instance
+// as a child of itself or of one of its descendants.
+func (h *Health) AddChild(name string, child *Health) error {
+	healthChildrenMu.Lock()
+	defer healthChildrenMu.Unlock()
+
+	if child.reaches(h) {
+		return ErrHealthChildCycle
+	}
+
+	if _, ok := h.child(name); ok {
+		return ErrHealthChildAlreadyRegistered
+	}
+
+	notifications, cancelNotifications := child.Subscribe()
+	changes, cancelChanges := child.SubscribeChanges()
+	done := make(chan struct{})
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			cancelNotifications()
+			cancelChanges()
+			close(done)
+		})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.children = append(h.children, healthChild{name: name, health: child, cancel: cancel})
+	h.notify()
+
+	go h.forwardChildNotifications(notifications, done)
+	go h.forwardChildChanges(name, changes)
+	return nil
+}
+
+// RemoveChild removes the child health instance added under the given name. The child
+// no longer contributes to the health of this instance and its changes are no longer
+// propagated to the subscribers of this instance.
+func (h *Health) RemoveChild(name string) error {
+	healthChildrenMu.Lock()
+	defer healthChildrenMu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, child := range h.children {
+		if child.name == name {
+			h.children = append(h.children[:i:i], h.children[i+1:]...)
+			child.cancel()
+			h.notify()
+			return nil
+		}
+	}
+
+	return ErrHealthChildNotRegistered
+}
+
+// reaches returns true if the given health instance is this instance or one of its
+// descendants. Callers MUST lock healthChildrenMu.
+func (h *Health) reaches(target *Health) bool {
+	if h == target {
+		return true
+	}
+
+	h.mu.Lock()
+	children := h.childList()
+	h.mu.Unlock()
+
+	for _, child := range children {
+		if child.health.reaches(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// child returns the child health instance with the given name.
+func (h *Health) child(name string) (*Health, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, child := range h.children {
+		if child.name == name {
+			return child.health, true
+		}
+	}
+
+	return nil, false
+}
+
+// childList returns a copy of the child health instances. Callers MUST lock h.mu.
+func (h *Health) childList() []healthChild {
+	children := make([]healthChild, len(h.children))
+	copy(children, h.children)
+	return children
+}
+
+// forwardChildNotifications signals the subscribers of this health instance each time
+// a value is received from the given child notification channel until the given done
+// channel is closed.
+func (h *Health) forwardChildNotifications(notifications <-chan struct{}, done <-chan struct{}) {
+	for {
+		select {
+		case <-notifications:
+			h.mu.Lock()
+			h.notify()
+			h.mu.Unlock()
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// forwardChildChanges delivers the changes received from the given child change channel
+// to the change subscribers of this health instance. The keys of forwarded changes are
+// namespaced by the given child name.
+func (h *Health) forwardChildChanges(name string, changes <-chan HealthChange) {
+	for change := range changes {
+		change.Key = ChildHealthKey{Name: name, Key: change.Key}
+
+		h.mu.Lock()
+		h.notify(change)
+		h.mu.Unlock()
+	}
+}
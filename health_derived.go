@@ -0,0 +1,90 @@
+package process
+
+// HealthRule computes the status of a derived health component from the current
+// statuses of its dependencies, given in the order the dependencies were supplied.
+type HealthRule func(healthy []bool) bool
+
+// AllOf returns a rule that is satisfied when every dependency is healthy.
+func AllOf() HealthRule {
+	return func(healthy []bool) bool {
+		return countHealthy(healthy) == len(healthy)
+	}
+}
+
+// AnyOf returns a rule that is satisfied when at least one dependency is healthy.
+func AnyOf() HealthRule {
+	return Quorum(1)
+}
+
+// Quorum returns a rule that is satisfied when at least n dependencies are healthy.
+func Quorum(n int) HealthRule {
+	return func(healthy []bool) bool {
+		return countHealthy(healthy) >= n
+	}
+}
+
+// countHealthy returns the number of true values in the given slice.
+func countHealthy(healthy []bool) int {
+	n := 0
+	for _, v := range healthy {
+		if v {
+			n++
+		}
+	}
+
+	return n
+}
+
+// RegisterDerived creates and returns a new component status value for the given key
+// whose status is computed by the given rule from the components registered to the
+// given dependency keys. The status is recomputed each time the status of a dependency
+// changes, and should not be updated directly. Each dependency must be registered prior
+// to the derived component. Dependencies are resolved by key on each evaluation, so a
+// dependency that is unregistered is treated as unhealthy until it is registered again.
+// It an error to register the same key twice.
+func (h *Health) RegisterDerived(key interface{}, rule HealthRule, deps ...interface{}) (*HealthComponentStatus, error) {
+	if _, err := h.GetAll(deps...); err != nil {
+		return nil, err
+	}
+
+	component, err := h.Register(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deps) == 0 {
+		component.Update(rule(nil))
+		return component, nil
+	}
+
+	// Subscribe prior to computing the initial status so that no change is missed
+	changes, cancel := h.SubscribeChanges(deps...)
+	component.Update(h.evaluateHealthRule(rule, deps))
+
+	go func() {
+		defer cancel()
+
+		for {
+			select {
+			case <-changes:
+				component.Update(h.evaluateHealthRule(rule, deps))
+			case <-component.unregistered:
+				return
+			}
+		}
+	}()
+
+	return component, nil
+}
+
+// evaluateHealthRule applies the given rule to the current status of the components
+// registered to the given keys. Keys without a registered component are unhealthy.
+func (h *Health) evaluateHealthRule(rule HealthRule, keys []interface{}) bool {
+	healthy := make([]bool, 0, len(keys))
+	for _, key := range keys {
+		component, ok := h.Get(key)
+		healthy = append(healthy, ok && component.Healthy())
+	}
+
+	return rule(healthy)
+}
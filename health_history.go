@@ -0,0 +1,92 @@
+package process
+
+import "time"
+
+// defaultHealthHistorySize is the number of transitions retained for each health
+// component if the health instance does not configure an explicit history size.
+const defaultHealthHistorySize = 16
+
+// healthHistory is a bounded ring buffer of health status transitions.
+type healthHistory struct {
+	changes []HealthChange
+	start   int
+	size    int
+}
+
+func newHealthHistory(capacity int) *healthHistory {
+	return &healthHistory{
+		changes: make([]HealthChange, capacity),
+	}
+}
+
+// add appends the given change to the buffer, evicting the oldest change if the
+// buffer is full.
+func (h *healthHistory) add(change HealthChange) {
+	if len(h.changes) == 0 {
+		return
+	}
+
+	if h.size < len(h.changes) {
+		h.changes[(h.start+h.size)%len(h.changes)] = change
+		h.size++
+		return
+	}
+
+	h.changes[h.start] = change
+	h.start = (h.start + 1) % len(h.changes)
+}
+
+// values returns a new slice of the buffered changes ordered from oldest to newest.
+func (h *healthHistory) values() []HealthChange {
+	values := make([]HealthChange, 0, h.size)
+	for i := 0; i < h.size; i++ {
+		values = append(values, h.changes[(h.start+i)%len(h.changes)])
+	}
+
+	return values
+}
+
+// detectFlapping records a transition of the component's underlying status at the given
+// time and marks the component as flapping if the number of transitions within the flap
+// window of the health instance exceeds its threshold. This method no-ops if the health
+// instance was not configured with flap suppression. Callers MUST lock s.health.mu.
+func (s *HealthComponentStatus) detectFlapping(now time.Time) {
+	if s.health.flapWindow == 0 {
+		return
+	}
+
+	n := 0
+	for n < len(s.transitions) && now.Sub(s.transitions[n]) >= s.health.flapWindow {
+		n++
+	}
+	s.transitions = append(s.transitions[n:], now)
+
+	if !s.flapping && len(s.transitions) > s.health.flapMaxTransitions {
+		s.flapping = true
+		go s.watchFlapping()
+	}
+}
+
+// watchFlapping clears the flapping flag of the component once its underlying status has
+// not changed for an entire flap window.
+func (s *HealthComponentStatus) watchFlapping() {
+	for {
+		s.health.mu.Lock()
+		remaining := s.health.clock.Until(s.lastUpdated.Add(s.health.flapWindow))
+		if remaining <= 0 {
+			old := s.reportedHealthy()
+			s.flapping = false
+			s.transitions = nil
+			s.notifyIfChanged(old, s.health.clock.Now())
+			s.health.mu.Unlock()
+			return
+		}
+		s.health.mu.Unlock()
+
+		select {
+		case <-s.health.clock.After(remaining):
+		case <-s.unregistered:
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package process
+
+import (
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+type HealthConfigFunc func(*Health)
+
+// WithHealthHistorySize configures a Health instance to retain the given number of
+// the most recent status transitions of each registered component. A non-positive
+// size disables the history.
+func WithHealthHistorySize(size int) HealthConfigFunc {
+	if size < 0 {
+		size = 0
+	}
+
+	return func(h *Health) { h.historySize = size }
+}
+
+// WithFlapSuppression configures a Health instance to report a component as unhealthy
+// while it is flapping. A component is flapping once its status changes more than the
+// given number of times within the given window, and stops flapping once its status
+// has not changed for an entire window. Subscribers are not notified of the status
+// changes of a flapping component. A non-positive window or number of transitions
+// disables flap suppression.
+func WithFlapSuppression(window time.Duration, maxTransitions int) HealthConfigFunc {
+	if window <= 0 || maxTransitions <= 0 {
+		window, maxTransitions = 0, 0
+	}
+
+	return func(h *Health) {
+		h.flapWindow = window
+		h.flapMaxTransitions = maxTransitions
+	}
+}
+
+func withHealthClock(clock glock.Clock) HealthConfigFunc {
+	return func(h *Health) { h.clock = clock }
+}
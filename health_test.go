@@ -0,0 +1,359 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRegisterWithTTL(t *testing.T) {
+	clock := glock.NewMockClock()
+	health := NewHealth(withHealthClock(clock))
+
+	component, err := health.RegisterWithTTL("test", time.Second*5)
+	require.Nil(t, err)
+	assert.False(t, component.Healthy())
+
+	component.Heartbeat()
+	assert.True(t, component.Healthy())
+
+	clock.BlockingAdvance(time.Second * 3)
+	component.Heartbeat()
+	clock.BlockingAdvance(time.Second * 3)
+	assert.True(t, component.Healthy())
+
+	clock.BlockingAdvance(time.Second * 2)
+	assert.Eventually(t, func() bool { return !component.Healthy() }, time.Second, time.Millisecond)
+	assert.False(t, health.Healthy())
+
+	component.Heartbeat()
+	assert.True(t, component.Healthy())
+	assert.True(t, health.Healthy())
+}
+
+func TestHealthRegisterWithTTLAlreadyRegistered(t *testing.T) {
+	health := NewHealth()
+
+	_, err := health.Register("test")
+	require.Nil(t, err)
+
+	_, err = health.RegisterWithTTL("test", time.Second)
+	assert.Equal(t, ErrHealthComponentAlreadyRegistered, err)
+}
+
+func TestHealthRegisterWithTTLNonPositive(t *testing.T) {
+	health := NewHealth()
+
+	_, err := health.RegisterWithTTL("test", 0)
+	assert.Equal(t, ErrHealthComponentInvalidTTL, err)
+
+	_, err = health.RegisterWithTTL("test", -time.Second)
+	assert.Equal(t, ErrHealthComponentInvalidTTL, err)
+
+	_, ok := health.Get("test")
+	assert.False(t, ok)
+}
+
+func TestHealthSubscribeChanges(t *testing.T) {
+	clock := glock.NewMockClock()
+	health := NewHealth(withHealthClock(clock))
+	a, _ := health.Register("a")
+	b, _ := health.Register("b")
+
+	ch, cancel := health.SubscribeChanges("a")
+	defer cancel()
+
+	b.Update(true)
+	a.Update(true)
+	clock.Advance(time.Second)
+	a.Update(false)
+
+	assert.Equal(t, HealthChange{Key: "a", Old: false, New: true, At: clock.Now().Add(-time.Second)}, <-ch)
+	assert.Equal(t, HealthChange{Key: "a", Old: true, New: false, At: clock.Now()}, <-ch)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestHealthSubscribeChangesAllKeys(t *testing.T) {
+	health := NewHealth()
+	a, _ := health.Register("a")
+	b, _ := health.Register("b")
+
+	ch, cancel := health.SubscribeChanges()
+	defer cancel()
+
+	a.Update(true)
+	b.Update(true)
+	b.Update(true)
+
+	assert.Equal(t, "a", (<-ch).Key)
+	assert.Equal(t, "b", (<-ch).Key)
+
+	select {
+	case change := <-ch:
+		t.Fatalf("unexpected change %v", change)
+	default:
+	}
+}
+
+func TestHealthSubscribeReusesSlots(t *testing.T) {
+	health := NewHealth()
+
+	for i := 0; i < 10; i++ {
+		_, cancel := health.Subscribe()
+		cancel()
+
+		_, cancelChanges := health.SubscribeChanges()
+		cancelChanges()
+	}
+
+	assert.Len(t, health.subscribers, 1)
+	assert.Len(t, health.changeSubscribers, 1)
+}
+
+func TestHealthComponentHistory(t *testing.T) {
+	clock := glock.NewMockClock()
+	health := NewHealth(withHealthClock(clock), WithHealthHistorySize(3))
+	component, _ := health.Register("test")
+
+	var expected []HealthChange
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Second)
+		component.Update(i%2 == 0)
+		expected = append(expected, HealthChange{Key: "test", Old: i%2 != 0, New: i%2 == 0, At: clock.Now()})
+	}
+
+	component.Update(true)
+	assert.Equal(t, expected[2:], component.History())
+}
+
+func TestHealthFlapSuppression(t *testing.T) {
+	clock := glock.NewMockClock()
+	health := NewHealth(withHealthClock(clock), WithFlapSuppression(time.Second*10, 3))
+	component, _ := health.Register("test")
+
+	ch, cancel := health.SubscribeChanges()
+	defer cancel()
+
+	component.Update(true)
+	component.Update(false)
+	component.Update(true)
+	assert.True(t, component.Healthy())
+
+	component.Update(false)
+	component.Update(true)
+	assert.False(t, component.Healthy())
+	assert.False(t, health.Healthy())
+	assert.Len(t, component.History(), 5)
+
+	clock.BlockingAdvance(time.Second * 10)
+	assert.Eventually(t, component.Healthy, time.Second, time.Millisecond)
+
+	var changes []bool
+	for i := 0; i < 5; i++ {
+		changes = append(changes, (<-ch).New)
+	}
+	assert.Equal(t, []bool{true, false, true, false, true}, changes)
+}
+
+func TestHealthHistoryAndFlapSuppressionNonPositive(t *testing.T) {
+	for _, config := range []HealthConfigFunc{
+		WithHealthHistorySize(-1),
+		WithFlapSuppression(0, 2),
+		WithFlapSuppression(time.Second, 0),
+		WithFlapSuppression(-time.Second, -1),
+	} {
+		health := NewHealth(config)
+		component, err := health.Register("test")
+		require.Nil(t, err)
+
+		component.Update(true)
+		component.Update(false)
+		component.Update(true)
+		assert.True(t, component.Healthy())
+	}
+
+	health := NewHealth(WithHealthHistorySize(-1))
+	component, _ := health.Register("test")
+	component.Update(true)
+	assert.Empty(t, component.History())
+}
+
+func TestHealthAddChild(t *testing.T) {
+	parent := NewHealth()
+	child := NewHealth()
+	grandchild := NewHealth()
+	require.Nil(t, parent.AddChild("child", child))
+	require.Nil(t, child.AddChild("grandchild", grandchild))
+	assert.Equal(t, ErrHealthChildAlreadyRegistered, parent.AddChild("child", NewHealth()))
+
+	a, _ := parent.Register("a")
+	b, _ := child.Register("b")
+	c, _ := grandchild.Register("c")
+	a.Update(true)
+	b.Update(true)
+	assert.False(t, parent.Healthy())
+	assert.False(t, child.Healthy())
+
+	key := ChildHealthKey{Name: "child", Key: ChildHealthKey{Name: "grandchild", Key: "c"}}
+	assert.Equal(t, "child/grandchild/c", key.String())
+
+	components, err := parent.GetAll("a", ChildHealthKey{Name: "child", Key: "b"}, key)
+	require.Nil(t, err)
+	assert.Equal(t, []*HealthComponentStatus{a, b, c}, components)
+
+	_, err = parent.GetAll(ChildHealthKey{Name: "missing", Key: "b"})
+	assert.EqualError(t, err, `health component "missing/b" not registered`)
+
+	ch, cancel := parent.SubscribeChanges(key)
+	defer cancel()
+
+	c.Update(true)
+	assert.True(t, parent.Healthy())
+
+	change := <-ch
+	assert.Equal(t, key, change.Key)
+	assert.True(t, change.New)
+}
+
+func TestHealthAddChildCycle(t *testing.T) {
+	parent := NewHealth()
+	child := NewHealth()
+	grandchild := NewHealth()
+	require.Nil(t, parent.AddChild("child", child))
+	require.Nil(t, child.AddChild("grandchild", grandchild))
+
+	assert.Equal(t, ErrHealthChildCycle, parent.AddChild("self", parent))
+	assert.Equal(t, ErrHealthChildCycle, child.AddChild("parent", parent))
+	assert.Equal(t, ErrHealthChildCycle, grandchild.AddChild("parent", parent))
+	assert.Len(t, parent.Keys(), 0)
+}
+
+func TestHealthRemoveChild(t *testing.T) {
+	parent := NewHealth()
+	child := NewHealth()
+	require.Nil(t, parent.AddChild("child", child))
+
+	a, _ := child.Register("a")
+	assert.False(t, parent.Healthy())
+
+	ch, cancel := parent.SubscribeChanges()
+	defer cancel()
+
+	require.Nil(t, parent.RemoveChild("child"))
+	assert.Equal(t, ErrHealthChildNotRegistered, parent.RemoveChild("child"))
+	assert.True(t, parent.Healthy())
+	assert.Len(t, parent.Keys(), 0)
+
+	// Changes to a removed child are no longer propagated
+	a.Update(true)
+	assert.Never(t, func() bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}, time.Millisecond*50, time.Millisecond)
+
+	// The former child may be added again, including as the parent of its former parent
+	require.Nil(t, child.AddChild("parent", parent))
+}
+
+func TestHealthRegisterDerived(t *testing.T) {
+	health := NewHealth()
+	db, _ := health.Register("db")
+	shard1, _ := health.Register("shard-1")
+	shard2, _ := health.Register("shard-2")
+	_, _ = health.Register("shard-3")
+
+	_, err := health.RegisterDerived("cache", Quorum(2), "shard-1", "shard-2", "shard-3")
+	require.Nil(t, err)
+	api, err := health.RegisterDerived("api", AllOf(), "db", "cache")
+	require.Nil(t, err)
+	assert.False(t, api.Healthy())
+
+	db.Update(true)
+	shard1.Update(true)
+	assert.Never(t, api.Healthy, time.Millisecond*50, time.Millisecond)
+
+	shard2.Update(true)
+	assert.Eventually(t, api.Healthy, time.Second, time.Millisecond)
+
+	db.Update(false)
+	assert.Eventually(t, func() bool { return !api.Healthy() }, time.Second, time.Millisecond)
+
+	_, err = health.RegisterDerived("other", AnyOf(), "db", "missing")
+	assert.EqualError(t, err, `health component "missing" not registered`)
+}
+
+func TestHealthUnregisterNotifiesChange(t *testing.T) {
+	clock := glock.NewMockClock()
+	health := NewHealth(withHealthClock(clock))
+	a, _ := health.Register("a")
+	_, _ = health.Register("b")
+	a.Update(true)
+
+	ch, cancel := health.SubscribeChanges("a", "b")
+	defer cancel()
+
+	// Removing an unhealthy component is not a transition
+	require.Nil(t, health.Unregister("b"))
+	require.Nil(t, health.Unregister("a"))
+	assert.Equal(t, HealthChange{Key: "a", Old: true, New: false, At: clock.Now()}, <-ch)
+}
+
+func TestHealthRegisterDerivedReregisteredDependency(t *testing.T) {
+	health := NewHealth()
+	db, _ := health.Register("db")
+	db.Update(true)
+
+	api, err := health.RegisterDerived("api", AllOf(), "db")
+	require.Nil(t, err)
+	assert.True(t, api.Healthy())
+
+	require.Nil(t, health.Unregister("db"))
+	assert.Eventually(t, func() bool { return !api.Healthy() }, time.Second, time.Millisecond)
+
+	db, err = health.Register("db")
+	require.Nil(t, err)
+	db.Update(true)
+	assert.Eventually(t, api.Healthy, time.Second, time.Millisecond)
+}
+
+func TestHealthRules(t *testing.T) {
+	assert.True(t, AllOf()(nil))
+	assert.True(t, AllOf()([]bool{true, true}))
+	assert.False(t, AllOf()([]bool{true, false}))
+	assert.False(t, AnyOf()([]bool{false, false}))
+	assert.True(t, AnyOf()([]bool{false, true}))
+	assert.False(t, Quorum(2)([]bool{true, false, false}))
+	assert.True(t, Quorum(2)([]bool{true, false, true}))
+}
+
+func TestHealthUnregister(t *testing.T) {
+	health := NewHealth()
+	a, _ := health.Register("a")
+	_, _ = health.Register("b")
+	assert.False(t, health.Healthy())
+
+	require.Nil(t, health.Unregister("b"))
+	assert.Equal(t, ErrHealthComponentNotRegistered, health.Unregister("b"))
+
+	a.Update(true)
+	assert.True(t, health.Healthy())
+
+	_, ok := health.Get("b")
+	assert.False(t, ok)
+
+	b, err := health.Register("b")
+	require.Nil(t, err)
+	require.Nil(t, health.Unregister("b"))
+	b.Update(true)
+	assert.False(t, b.Healthy())
+}
@@ -14,13 +14,6 @@ import (
 var testErr1 = errors.New("oops1")
 var testErr2 = errors.New("oops2")
 
-// type testLogger struct{}
-
-// func (l testLogger) WithFields(LogFields) Logger             { return l }
-// func (l testLogger) Info(msg string, args ...interface{})    { fmt.Printf("INFO: "+msg+"\n", args...) }
-// func (l testLogger) Warning(msg string, args ...interface{}) { fmt.Printf("WARN: "+msg+"\n", args...) }
-// func (l testLogger) Error(msg string, args ...interface{})   { fmt.Printf("ERROR: "+msg+"\n", args...) }
-
 // forwardN returns a channel that proxies the next n values read from the
 // given channel. The returned channel will be closed after the nth value
 // has been written.
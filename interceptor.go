@@ -0,0 +1,41 @@
+package process
+
+import "context"
+
+// HookInfo describes a lifecycle hook of a process that is about to be invoked.
+type HookInfo struct {
+	// Name is the name of the process.
+	Name string
+
+	// Phase is the lifecycle phase of the hook (one of the values in Phases).
+	Phase string
+
+	// Priority is the priority of the process.
+	Priority int
+
+	// Metadata is the metadata the process was tagged with.
+	Metadata map[string]interface{}
+}
+
+// Interceptor wraps the invocation of a lifecycle hook of a process. An interceptor
+// must call next to invoke the hook (or the next interceptor), and may alter the
+// context passed to the hook or the error value returned from it.
+type Interceptor func(ctx context.Context, info HookInfo, next func(ctx context.Context) error) error
+
+// intercept wraps the given hook function with the interceptors of the process. The
+// first interceptor is the outermost.
+func (m *Meta) intercept(phase string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	info := HookInfo{
+		Name:     m.Name(),
+		Phase:    phase,
+		Priority: m.options.priority,
+		Metadata: copyMetadata(m.options.metadata),
+	}
+
+	for i := len(m.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := m.interceptors[i], fn
+		fn = func(ctx context.Context) error { return interceptor(ctx, info, next) }
+	}
+
+	return fn
+}
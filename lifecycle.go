@@ -0,0 +1,140 @@
+package process
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// The names of the lifecycle phases of a process.
+const (
+	PhaseInject   = "inject"
+	PhaseInit     = "init"
+	PhaseRun      = "run"
+	PhaseStop     = "stop"
+	PhaseFinalize = "finalize"
+)
+
+// Phases is the list of lifecycle phases of a process, in the order they occur.
+var Phases = []string{PhaseInject, PhaseInit, PhaseRun, PhaseStop, PhaseFinalize}
+
+// Lifecycle is a snapshot of the lifecycle of a process.
+type Lifecycle struct {
+	// Phase is the most recently entered lifecycle phase, or the empty string if
+	// no phase has been entered.
+	Phase string
+
+	// Restarts is the number of times the process has been restarted.
+	Restarts int
+
+	// PhaseDurations holds the duration of each completed lifecycle phase.
+	PhaseDurations map[string]time.Duration
+
+	// LastErrorKind classifies the most recent error returned by a lifecycle
+	// phase, or is the empty string if no error has occurred.
+	LastErrorKind string
+}
+
+// lifecycle tracks the lifecycle of a process.
+type lifecycle struct {
+	mu            sync.Mutex
+	phase         string
+	restarts      int
+	durations     map[string]time.Duration
+	lastErrorKind string
+}
+
+// Lifecycle returns a snapshot of the process's lifecycle.
+func (m *Meta) Lifecycle() Lifecycle {
+	m.lifecycle.mu.Lock()
+	defer m.lifecycle.mu.Unlock()
+
+	durations := make(map[string]time.Duration, len(m.lifecycle.durations))
+	for phase, duration := range m.lifecycle.durations {
+		durations[phase] = duration
+	}
+
+	return Lifecycle{
+		Phase:          m.lifecycle.phase,
+		Restarts:       m.lifecycle.restarts,
+		PhaseDurations: durations,
+		LastErrorKind:  m.lifecycle.lastErrorKind,
+	}
+}
+
+// beginPhase marks the given phase as entered and returns the time it was entered.
+func (m *Meta) beginPhase(phase string) time.Time {
+	m.lifecycle.mu.Lock()
+	defer m.lifecycle.mu.Unlock()
+
+	m.lifecycle.phase = phase
+	return m.options.lifecycleClock.Now()
+}
+
+// endPhase records the duration of the given phase entered at the given time, as well
+// as the kind of the given error value, if non-nil. The timeline and metrics are updated
+// after the lifecycle is unlocked so that they may inspect the process's lifecycle.
+func (m *Meta) endPhase(phase string, start time.Time, err error) {
+	duration := m.options.lifecycleClock.Since(start)
+
+	m.lifecycle.mu.Lock()
+	if m.lifecycle.durations == nil {
+		m.lifecycle.durations = map[string]time.Duration{}
+	}
+	m.lifecycle.durations[phase] = duration
+	m.recordErrorLocked(err)
+	m.lifecycle.mu.Unlock()
+
+	m.recordTimeline(phase, start, start.Add(duration), err)
+	m.metrics.PhaseDuration(m.Name(), phase, duration, err)
+}
+
+// recordError records the kind of the given error value, if non-nil.
+func (m *Meta) recordError(err error) {
+	m.lifecycle.mu.Lock()
+	defer m.lifecycle.mu.Unlock()
+
+	m.recordErrorLocked(err)
+}
+
+// recordErrorLocked records the kind of the given error value, if non-nil. Callers
+// MUST lock m.lifecycle.mu.
+func (m *Meta) recordErrorLocked(err error) {
+	if err != nil {
+		m.lifecycle.lastErrorKind = errorKind(err)
+	}
+}
+
+// recordRestart increments the process's restart count.
+func (m *Meta) recordRestart() {
+	m.lifecycle.mu.Lock()
+	m.lifecycle.restarts++
+	m.lifecycle.mu.Unlock()
+
+	m.metrics.Restart(m.Name())
+}
+
+// errorKind returns a short, stable classification of the given error value.
+func errorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrWatchdogTimeout):
+		return "watchdog_timeout"
+	case errors.Is(err, ErrStartupTimeout):
+		return "startup_timeout"
+	case errors.Is(err, ErrShutdownTimeout):
+		return "shutdown_timeout"
+	case errors.Is(err, ErrUnexpectedReturn):
+		return "unexpected_return"
+	case errors.Is(err, ErrHealthCheckCanceled):
+		return "health_check_canceled"
+	}
+
+	var opErr *opError
+	if errors.As(err, &opErr) {
+		return opErr.message
+	}
+
+	return "error"
+}
@@ -0,0 +1,276 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// listenFDsStart is the first file descriptor passed by the service manager.
+const listenFDsStart = 3
+
+// Listeners is a registry of named network listeners shared with processes. Listeners
+// may be inherited from the service manager via socket activation or created on demand.
+// The registry also holds packet-oriented connections (e.g., UDP sockets) inherited from
+// the service manager. Listeners and packet connections share a single namespace.
+type Listeners struct {
+	mu          sync.Mutex
+	listeners   map[string]net.Listener
+	packetConns map[string]net.PacketConn
+	ready       *os.File
+}
+
+// NewListeners creates an empty listener registry.
+func NewListeners() *Listeners {
+	return &Listeners{
+		listeners:   map[string]net.Listener{},
+		packetConns: map[string]net.PacketConn{},
+	}
+}
+
+// NewListenersFromEnvironment creates a listener registry populated with the listeners
+// passed to this process via the systemd socket activation protocol ($LISTEN_PID,
+// $LISTEN_FDS, and $LISTEN_FDNAMES) or by the program this program replaced during an
+// upgrade (see State.Upgrade). Listeners without a name are registered under the name
+// LISTEN_FD_{fd}. A name may be shared by several sockets (e.g., a socket unit listening
+// on both IPv4 and IPv6 addresses); the first is registered under the name and the rest
+// under the name suffixed with ".1", ".2", and so on. Datagram sockets are registered as packet connections (see GetPacketConn),
+// and file descriptors that are neither stream nor datagram sockets are closed and ignored.
+// The environment variables are unset so that they are not passed to child processes. If
+// the variables are not set or are intended for another process, an empty registry is
+// returned.
+func NewListenersFromEnvironment() (*Listeners, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+		os.Unsetenv(upgradeListenFDsEnv)
+		os.Unsetenv(upgradeListenFDNamesEnv)
+		os.Unsetenv(upgradeReadyFDEnv)
+	}()
+
+	if listeners, ok, err := newListenersFromUpgrade(); ok {
+		return listeners, err
+	}
+
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return NewListeners(), nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return NewListeners(), nil
+	}
+
+	var names []string
+	if value := os.Getenv("LISTEN_FDNAMES"); value != "" {
+		names = strings.Split(value, ":")
+	}
+
+	files := make([]*os.File, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFDsStart + i
+
+		name := fmt.Sprintf("LISTEN_FD_%d", fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		files = append(files, os.NewFile(uintptr(fd), name))
+	}
+
+	return newListenersFromFiles(files)
+}
+
+// newListenersFromFiles creates a listener registry populated with listeners created
+// from the given files, registered under the name of each file. Files sharing a name are
+// registered under distinct names (see availableName). The files are closed.
+func newListenersFromFiles(files []*os.File) (*Listeners, error) {
+	listeners := NewListeners()
+
+	var err error
+	for _, file := range files {
+		if err == nil {
+			err = listeners.registerFile(listeners.availableName(file.Name()), file)
+		} else {
+			file.Close()
+		}
+	}
+
+	if err != nil {
+		listeners.Close()
+		return nil, err
+	}
+
+	return listeners, nil
+}
+
+// registerFile registers a listener or packet connection created from the given file.
+// Files that are neither stream nor datagram sockets are ignored. The file is closed
+// once the listener or packet connection is created.
+func (l *Listeners) registerFile(name string, file *os.File) error {
+	defer file.Close()
+
+	if listener, err := net.FileListener(file); err == nil {
+		if err := l.Register(name, listener); err != nil {
+			listener.Close()
+			return err
+		}
+
+		return nil
+	}
+
+	if conn, err := net.FilePacketConn(file); err == nil {
+		if err := l.RegisterPacketConn(name, conn); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Register adds the given listener to the registry under the given name.
+func (l *Listeners) Register(name string, listener net.Listener) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.registered(name) {
+		return ErrListenerAlreadyRegistered
+	}
+
+	l.listeners[name] = listener
+	return nil
+}
+
+// RegisterPacketConn adds the given packet connection to the registry under the given name.
+func (l *Listeners) RegisterPacketConn(name string, conn net.PacketConn) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.registered(name) {
+		return ErrListenerAlreadyRegistered
+	}
+
+	l.packetConns[name] = conn
+	return nil
+}
+
+// availableName returns the given name if no listener or packet connection is registered
+// under it. Otherwise, the name is suffixed with the smallest positive integer that makes
+// it unused (e.g., "http.1" for the second file named "http").
+func (l *Listeners) availableName(name string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	candidate := name
+	for i := 1; l.registered(candidate); i++ {
+		candidate = fmt.Sprintf("%s.%d", name, i)
+	}
+
+	return candidate
+}
+
+// registered returns true if a listener or packet connection is registered under the
+// given name. Callers MUST lock l.mu.
+func (l *Listeners) registered(name string) bool {
+	_, isListener := l.listeners[name]
+	_, isPacketConn := l.packetConns[name]
+	return isListener || isPacketConn
+}
+
+// Listen returns the listener registered under the given name. If no such listener
+// exists, a new listener is created on the given network and address and is registered
+// under the given name.
+func (l *Listeners) Listen(name, network, address string) (net.Listener, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if listener, ok := l.listeners[name]; ok {
+		return listener, nil
+	}
+	if _, ok := l.packetConns[name]; ok {
+		return nil, ErrListenerAlreadyRegistered
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	l.listeners[name] = listener
+	return listener, nil
+}
+
+// Get returns the listener registered under the given name.
+func (l *Listeners) Get(name string) (net.Listener, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	listener, ok := l.listeners[name]
+	return listener, ok
+}
+
+// GetPacketConn returns the packet connection registered under the given name.
+func (l *Listeners) GetPacketConn(name string) (net.PacketConn, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	conn, ok := l.packetConns[name]
+	return conn, ok
+}
+
+// Names returns the names of the registered listeners in lexicographic order.
+func (l *Listeners) Names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, 0, len(l.listeners))
+	for name := range l.listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// PacketConnNames returns the names of the registered packet connections in lexicographic
+// order.
+func (l *Listeners) PacketConnNames() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, 0, len(l.packetConns))
+	for name := range l.packetConns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Close closes every registered listener and packet connection and empties the registry.
+func (l *Listeners) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	for name, listener := range l.listeners {
+		if err := listener.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("listener %q: %w", name, err))
+		}
+	}
+	for name, conn := range l.packetConns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("packet connection %q: %w", name, err))
+		}
+	}
+
+	l.listeners = map[string]net.Listener{}
+	l.packetConns = map[string]net.PacketConn{}
+	return errorOrNil(errs)
+}
@@ -0,0 +1,146 @@
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenersListen(t *testing.T) {
+	listeners := NewListeners()
+	defer listeners.Close()
+
+	listener, err := listeners.Listen("http", "tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	again, err := listeners.Listen("http", "tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	assert.Equal(t, listener, again)
+
+	other, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	require.Nil(t, listeners.Register("admin", other))
+	assert.Equal(t, ErrListenerAlreadyRegistered, listeners.Register("admin", other))
+
+	registered, ok := listeners.Get("admin")
+	assert.True(t, ok)
+	assert.Equal(t, other, registered)
+	assert.Equal(t, []string{"admin", "http"}, listeners.Names())
+
+	require.Nil(t, listeners.Close())
+	assert.Empty(t, listeners.Names())
+
+	_, err = listener.Accept()
+	assert.NotNil(t, err)
+}
+
+func TestListenersFromFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("listeners cannot be created from files on this platform")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	file, err := listener.(*net.TCPListener).File()
+	require.Nil(t, err)
+
+	listeners, err := newListenersFromFiles([]*os.File{file})
+	require.Nil(t, err)
+	defer listeners.Close()
+
+	inherited, ok := listeners.Get(file.Name())
+	require.True(t, ok)
+	assert.Equal(t, listener.Addr().String(), inherited.Addr().String())
+
+	go func() {
+		if conn, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := inherited.Accept()
+	require.Nil(t, err)
+	conn.Close()
+}
+
+func TestListenersFromFilesMixed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("listeners cannot be created from files on this platform")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	listenerFile, err := listener.(*net.TCPListener).File()
+	require.Nil(t, err)
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer packetConn.Close()
+	packetConnFile, err := packetConn.(*net.UDPConn).File()
+	require.Nil(t, err)
+
+	otherFile, err := os.Open(os.DevNull)
+	require.Nil(t, err)
+
+	// A datagram socket or other file does not prevent the stream socket from being inherited
+	listeners, err := newListenersFromFiles([]*os.File{packetConnFile, otherFile, listenerFile})
+	require.Nil(t, err)
+	defer listeners.Close()
+
+	assert.Equal(t, []string{listenerFile.Name()}, listeners.Names())
+	assert.Equal(t, []string{packetConnFile.Name()}, listeners.PacketConnNames())
+
+	inherited, ok := listeners.GetPacketConn(packetConnFile.Name())
+	require.True(t, ok)
+	assert.Equal(t, packetConn.LocalAddr().String(), inherited.LocalAddr().String())
+
+	assert.Equal(t, ErrListenerAlreadyRegistered, listeners.Register(packetConnFile.Name(), listener))
+	_, err = listeners.Listen(packetConnFile.Name(), "tcp", "127.0.0.1:0")
+	assert.Equal(t, ErrListenerAlreadyRegistered, err)
+}
+
+func TestNewListenersFromEnvironmentOtherProcess(t *testing.T) {
+	os.Setenv("LISTEN_PID", "1")
+	os.Setenv("LISTEN_FDS", "1")
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	listeners, err := NewListenersFromEnvironment()
+	require.Nil(t, err)
+	assert.Empty(t, listeners.Names())
+
+	_, ok := os.LookupEnv("LISTEN_FDS")
+	assert.False(t, ok)
+}
+
+func TestRunListeners(t *testing.T) {
+	listeners := NewListeners()
+	injected := make(chan *Listeners, 1)
+	initialized := make(chan *Listeners, 1)
+
+	builder := NewContainerBuilder()
+	initializer := NewMockMaximumProcess()
+	initializer.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		initialized <- ListenersFromContext(ctx)
+		return nil
+	})
+	builder.RegisterInitializer(initializer, WithMetaName("i"))
+
+	injecter := InjecterFunc(func(ctx context.Context, meta *Meta) error {
+		injected <- ListenersFromContext(ctx)
+		return nil
+	})
+
+	state := Run(context.Background(), builder.Build(), WithInjecter(injecter), WithListeners(listeners))
+	require.True(t, state.Wait(context.Background()))
+	assert.Same(t, listeners, <-injected)
+	assert.Same(t, listeners, <-initialized)
+}
@@ -0,0 +1,47 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package process
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenersFromFilesRepeatedNames(t *testing.T) {
+	var files []*os.File
+	var addrs []string
+	for i := 0; i < 3; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+		defer listener.Close()
+
+		file, err := listener.(*net.TCPListener).File()
+		require.Nil(t, err)
+		defer file.Close()
+
+		// Socket units with several listen directives pass each socket under the same name
+		fd, err := syscall.Dup(int(file.Fd()))
+		require.Nil(t, err)
+
+		files = append(files, os.NewFile(uintptr(fd), "http"))
+		addrs = append(addrs, listener.Addr().String())
+	}
+
+	listeners, err := newListenersFromFiles(files)
+	require.Nil(t, err)
+	defer listeners.Close()
+
+	assert.Equal(t, []string{"http", "http.1", "http.2"}, listeners.Names())
+
+	for i, name := range []string{"http", "http.1", "http.2"} {
+		inherited, ok := listeners.Get(name)
+		require.True(t, ok)
+		assert.Equal(t, addrs[i], inherited.Addr().String())
+	}
+}
@@ -7,4 +7,61 @@ type Logger interface {
 	Error(string, ...interface{})
 }
 
+// DebugLogger is an optional extension of Logger that supports debug messages. Debug
+// messages written to a logger that does not implement this interface are discarded.
+type DebugLogger interface {
+	Logger
+	Debug(string, ...interface{})
+}
+
 type LogFields map[string]interface{}
+
+// LogLevel is the severity of a log message.
+type LogLevel int
+
+// The log levels, ordered from most to least verbose.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarning
+	LogLevelError
+)
+
+// leveledLogger discards messages below a minimum level before writing them to the
+// wrapped logger.
+type leveledLogger struct {
+	logger Logger
+	level  LogLevel
+}
+
+func newLeveledLogger(logger Logger, level LogLevel) DebugLogger {
+	return leveledLogger{logger: logger, level: level}
+}
+
+func (l leveledLogger) WithFields(fields LogFields) Logger {
+	return newLeveledLogger(l.logger.WithFields(fields), l.level)
+}
+
+func (l leveledLogger) Debug(msg string, args ...interface{}) {
+	if debugLogger, ok := l.logger.(DebugLogger); ok && l.level <= LogLevelDebug {
+		debugLogger.Debug(msg, args...)
+	}
+}
+
+func (l leveledLogger) Info(msg string, args ...interface{}) {
+	if l.level <= LogLevelInfo {
+		l.logger.Info(msg, args...)
+	}
+}
+
+func (l leveledLogger) Warning(msg string, args ...interface{}) {
+	if l.level <= LogLevelWarning {
+		l.logger.Warning(msg, args...)
+	}
+}
+
+func (l leveledLogger) Error(msg string, args ...interface{}) {
+	if l.level <= LogLevelError {
+		l.logger.Error(msg, args...)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.21
+// +build go1.21
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// slogLogger writes messages to a structured logger from the log/slog package.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a logger that writes messages as records to the given slog
+// handler. Logger fields are converted into record attributes.
+func NewSlogLogger(handler slog.Handler) DebugLogger {
+	return &slogLogger{logger: slog.New(handler)}
+}
+
+func (l *slogLogger) WithFields(fields LogFields) Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for _, key := range sortedLogFieldKeys(fields) {
+		args = append(args, key, fields[key])
+	}
+
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+func (l *slogLogger) Debug(msg string, args ...interface{})   { l.log(slog.LevelDebug, msg, args...) }
+func (l *slogLogger) Info(msg string, args ...interface{})    { l.log(slog.LevelInfo, msg, args...) }
+func (l *slogLogger) Warning(msg string, args ...interface{}) { l.log(slog.LevelWarn, msg, args...) }
+func (l *slogLogger) Error(msg string, args ...interface{})   { l.log(slog.LevelError, msg, args...) }
+
+func (l *slogLogger) log(level slog.Level, msg string, args ...interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	l.logger.Log(ctx, level, fmt.Sprintf(msg, args...))
+}
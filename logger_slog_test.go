@@ -0,0 +1,32 @@
+//go:build go1.21
+// +build go1.21
+
+package process
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	logger := NewSlogLogger(handler)
+
+	logger.Debug("starting %s", "a")
+	logger.WithFields(LogFields{"b": 2, "a": 1}).Info("running %d", 3)
+	logger.Warning("slow")
+
+	assert.Equal(t, "level=INFO msg=\"running 3\" a=1 b=2\nlevel=WARN msg=slow\n", buf.String())
+}
@@ -0,0 +1,136 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/derision-test/glock"
+)
+
+// stdLogger writes messages to a standard library logger.
+type stdLogger struct {
+	logger *log.Logger
+	fields LogFields
+}
+
+// NewStdLogger creates a logger that writes messages to the given standard library
+// logger. Each message is prefixed with its level and suffixed with its fields as
+// space-separated key=value pairs, ordered by key.
+func NewStdLogger(logger *log.Logger) DebugLogger {
+	return &stdLogger{logger: logger}
+}
+
+func (l *stdLogger) WithFields(fields LogFields) Logger {
+	return &stdLogger{logger: l.logger, fields: mergeLogFields(l.fields, fields)}
+}
+
+func (l *stdLogger) Debug(msg string, args ...interface{})   { l.log("DEBUG", msg, args...) }
+func (l *stdLogger) Info(msg string, args ...interface{})    { l.log("INFO", msg, args...) }
+func (l *stdLogger) Warning(msg string, args ...interface{}) { l.log("WARNING", msg, args...) }
+func (l *stdLogger) Error(msg string, args ...interface{})   { l.log("ERROR", msg, args...) }
+
+func (l *stdLogger) log(level, msg string, args ...interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] ", level)
+	fmt.Fprintf(&b, msg, args...)
+
+	for _, key := range sortedLogFieldKeys(l.fields) {
+		fmt.Fprintf(&b, " %s=%v", key, l.fields[key])
+	}
+
+	l.logger.Print(b.String())
+}
+
+// jsonLogger writes messages to a writer as JSON objects, one per line.
+type jsonLogger struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	clock  glock.Clock
+	fields LogFields
+}
+
+// NewJSONLogger creates a logger that writes each message to the given writer as a
+// JSON object on its own line. Each object contains the time, level, and message, as
+// well as the logger's fields.
+func NewJSONLogger(w io.Writer) DebugLogger {
+	return newJSONLogger(w, defaultClock)
+}
+
+func newJSONLogger(w io.Writer, clock glock.Clock) DebugLogger {
+	return &jsonLogger{mu: &sync.Mutex{}, w: w, clock: clock}
+}
+
+func (l *jsonLogger) WithFields(fields LogFields) Logger {
+	return &jsonLogger{mu: l.mu, w: l.w, clock: l.clock, fields: mergeLogFields(l.fields, fields)}
+}
+
+func (l *jsonLogger) Debug(msg string, args ...interface{})   { l.log("debug", msg, args...) }
+func (l *jsonLogger) Info(msg string, args ...interface{})    { l.log("info", msg, args...) }
+func (l *jsonLogger) Warning(msg string, args ...interface{}) { l.log("warning", msg, args...) }
+func (l *jsonLogger) Error(msg string, args ...interface{})   { l.log("error", msg, args...) }
+
+func (l *jsonLogger) log(level, msg string, args ...interface{}) {
+	payload := make(map[string]interface{}, len(l.fields)+3)
+	for key, value := range l.fields {
+		payload[key] = jsonLogValue(value)
+	}
+	payload["time"] = l.clock.Now().UTC()
+	payload["level"] = level
+	payload["message"] = fmt.Sprintf(msg, args...)
+
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		serialized, _ = json.Marshal(map[string]interface{}{
+			"time":    payload["time"],
+			"level":   "error",
+			"message": fmt.Sprintf("failed to serialize log message: %s", err),
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(append(serialized, '\n'))
+}
+
+// jsonLogValue converts values that do not have a useful JSON representation into
+// their string form.
+func jsonLogValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return value
+}
+
+// mergeLogFields returns a new set of fields containing the given fields. Values in
+// the second set take precedence.
+func mergeLogFields(fields, overrides LogFields) LogFields {
+	merged := make(LogFields, len(fields)+len(overrides))
+	for key, value := range fields {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// sortedLogFieldKeys returns the keys of the given fields in lexicographic order.
+func sortedLogFieldKeys(fields LogFields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,39 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0))
+
+	logger.Debug("starting %s", "a")
+	logger.WithFields(LogFields{"b": 2, "a": 1}).Info("running %d", 3)
+	logger.WithFields(LogFields{"a": 1}).WithFields(LogFields{"a": 4}).Warning("slow")
+	logger.Error("failed")
+
+	assert.Equal(t, "[DEBUG] starting a\n[INFO] running 3 a=1 b=2\n[WARNING] slow a=4\n[ERROR] failed\n", buf.String())
+}
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	clock := glock.NewMockClockAt(time.Date(2023, 4, 30, 12, 0, 0, 0, time.UTC))
+	logger := newJSONLogger(&buf, clock)
+
+	logger.Debug("starting %s", "a")
+	logger.WithFields(LogFields{"count": 2, "err": errors.New("oops")}).Error("failed")
+
+	assert.Equal(t, ""+
+		`{"level":"debug","message":"starting a","time":"2023-04-30T12:00:00Z"}`+"\n"+
+		`{"count":2,"err":"oops","level":"error","message":"failed","time":"2023-04-30T12:00:00Z"}`+"\n",
+		buf.String(),
+	)
+}
@@ -3,11 +3,22 @@ package process
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 type machineBuilder struct {
-	injecter Injecter
-	health   *Health
+	injecter     Injecter
+	health       *Health
+	metrics      Metrics
+	tracer       Tracer
+	timeline     *timeline
+	logger       Logger
+	startup      *startupRecorder
+	interceptors []Interceptor
+	observers    []Observer
+	listeners    *Listeners
+	upgrader     *upgrader
+	observer     *observerNotifier
 }
 
 // closedErrorsChannel is a global, always closed channel of error values.
@@ -21,12 +32,19 @@ func newMachineBuilder(configs ...MachineConfigFunc) *machineBuilder {
 	b := &machineBuilder{
 		injecter: InjecterFunc(func(ctx context.Context, meta *Meta) error { return nil }),
 		health:   NewHealth(),
+		metrics:  NilMetrics,
+		tracer:   NilTracer,
+		timeline: newTimeline(),
+		logger:   NilLogger,
+		startup:  newStartupRecorder(),
+		upgrader: newUpgrader(),
 	}
 
 	for _, f := range configs {
 		f(b)
 	}
 
+	b.observer = newObserverNotifier(multiObserver(b.observers))
 	return b
 }
 
@@ -50,6 +68,9 @@ func newMachineBuilder(configs ...MachineConfigFunc) *machineBuilder {
 // all of the processes registered to this priority have started and the process becomes
 // healthy (or the health timeout for an unhealthyprocess elapses).
 //
+// Once the processes registered to every priority have become healthy, a report of
+// the time spent in each of the steps above is written to the builder's logger.
+//
 // On shutdown due to a user signal, an explicit request, or a process error, all of the
 // processes registered to the given container are finalized. All finalizer methods are
 // invoked in parallel.
@@ -59,13 +80,34 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 		n += len(meta)
 	}
 
+	for _, meta := range container.Meta() {
+		meta.metrics = b.metrics
+		meta.tracer = b.tracer
+		meta.machineHealth = newScopedHealth(b.health)
+		meta.timeline = b.timeline
+		meta.interceptors = append(append([]Interceptor(nil), b.interceptors...), meta.options.interceptors...)
+		meta.listeners = b.listeners
+	}
+
+	for i, priority := range container.priorities {
+		b.timeline.addLane(timelineLane{priorityIndex: i + 1}, priorityLaneName(priority))
+
+		for j, meta := range container.meta[priority] {
+			meta.timelineLane = timelineLane{priorityIndex: i + 1, index: j + 1}
+			b.timeline.addLane(meta.timelineLane, meta.Name())
+		}
+	}
+
 	var wg sync.WaitGroup
 	processErrors := make(chan error, n)
 	healthCheckCtx, healthCheckCancel := context.WithCancel(context.Background())
 
-	var initAndRunEachPriority []streamErrorFunc
-	for _, priority := range container.priorities {
+	initAndRunEachPriority := []streamErrorFunc{b.startup.begin()}
+	for i, priority := range container.priorities {
+		priority := priority
 		meta := container.meta[priority]
+		priorityLane := timelineLane{priorityIndex: i + 1}
+		priorityStartup := b.startup.addPriority(priority, meta)
 
 		var partitions [][]*Meta
 		if priority == 0 {
@@ -84,10 +126,18 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 						return nil
 					}
 
-					meta.logger.Info("Running inject hook for %s", meta.Name())
+					meta.logger.Debug("Running inject hook for %s", meta.Name())
+
+					if b.listeners != nil {
+						ctx = ContextWithListeners(ctx, b.listeners)
+					}
 
-					if err := b.injecter.Inject(ctx, meta); err != nil {
-						return &opError{
+					start := meta.beginPhase(PhaseInject)
+					err := meta.traced(ctx, PhaseInject, meta.intercept(PhaseInject, func(ctx context.Context) error {
+						return b.injecter.Inject(ctx, meta)
+					}))
+					if err != nil {
+						err = &opError{
 							source:   err,
 							metaName: meta.Name(),
 							opName:   "inject hook",
@@ -95,7 +145,8 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 						}
 					}
 
-					return nil
+					meta.endPhase(PhaseInject, start, err)
+					return err
 				})
 			})
 
@@ -104,8 +155,8 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 			})
 
 			initEachPriority = append(initEachPriority, chain(
-				injectAtPriority,
-				initAtPriority,
+				b.startup.timed(injectAtPriority, &priorityStartup.Inject),
+				b.startup.timed(initAtPriority, &priorityStartup.Init),
 			))
 		}
 
@@ -113,6 +164,15 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 			return func(ctx context.Context) <-chan error {
 				wg.Add(1)
 
+				meta.errorReporter = func(err error) {
+					wg.Add(1)
+
+					go func() {
+						defer wg.Done()
+						processErrors <- err
+					}()
+				}
+
 				go func() {
 					defer wg.Done()
 
@@ -137,7 +197,7 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 			healthKeys = append(healthKeys, key)
 		}
 
-		waitUntilHealthy := toStreamErrorFunc(func(ctx context.Context) error {
+		waitUntilHealthy := toStreamErrorFunc(func(ctx context.Context) (err error) {
 			components, err := b.health.GetAll(healthKeys...)
 			if err != nil {
 				return err
@@ -146,36 +206,56 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 				return nil
 			}
 
-			ch, cancel := b.health.Subscribe()
+			start := b.startup.clock.Now()
+			defer func() { b.timeline.record(priorityLane, "wait until healthy", start, b.startup.clock.Now(), err) }()
+
+			ch, cancel := b.health.SubscribeChanges(healthKeys...)
 			defer cancel()
 
-			for {
+			healthyAt := map[interface{}]time.Time{}
+			for !healthy(components) {
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
-				case <-ch:
+				case change := <-ch:
+					if change.New {
+						healthyAt[change.Key] = b.startup.clock.Now()
+					}
 				case <-healthCheckCtx.Done():
 					return ErrHealthCheckCanceled
 				}
+			}
 
-				healthy := true
-				for _, component := range components {
-					if !component.Healthy() {
-						healthy = false
-						break
+			for _, meta := range meta {
+				var wait time.Duration
+				for _, key := range meta.options.healthKeys {
+					if at, ok := healthyAt[key]; ok && at.Sub(start) > wait {
+						wait = at.Sub(start)
 					}
 				}
 
-				if healthy {
-					return nil
-				}
+				b.startup.recordHealthWait(priorityStartup, meta, wait)
 			}
+
+			return nil
+		})
+
+		notifyPriorityStarted := toStreamErrorFunc(func(ctx context.Context) error {
+			b.observer.OnPriorityStarted(priority)
+			return nil
+		})
+
+		notifyPriorityHealthy := toStreamErrorFunc(func(ctx context.Context) error {
+			b.observer.OnPriorityHealthy(priority)
+			return nil
 		})
 
 		initAndRunEachPriority = append(initAndRunEachPriority, chain(
+			notifyPriorityStarted,
 			chain(initEachPriority...),
-			runAtPriority,
-			waitUntilHealthy,
+			b.startup.timed(runAtPriority, &priorityStartup.Run),
+			b.startup.timed(waitUntilHealthy, &priorityStartup.HealthWait),
+			notifyPriorityHealthy,
 		))
 	}
 
@@ -195,10 +275,45 @@ func (b *machineBuilder) buildRun(container *Container) streamErrorFunc {
 		})
 	})
 
+	healthChanges, cancelHealthChanges := b.health.SubscribeChanges()
+	go func() {
+		for change := range healthChanges {
+			b.metrics.HealthTransition(change.Key, change.New)
+		}
+	}()
+
+	stopObservingHealth := toStreamErrorFunc(func(ctx context.Context) error {
+		cancelHealthChanges()
+		return nil
+	})
+
+	notifyApplicationReady := toStreamErrorFunc(func(ctx context.Context) error {
+		b.observer.OnApplicationReady()
+
+		if b.listeners != nil {
+			if err := b.listeners.notifyReady(); err != nil {
+				b.logger.Warning("Failed to report readiness to the upgrading program: %s", err)
+			}
+		}
+
+		return nil
+	})
+
+	notifyShutdownBegin := toStreamErrorFunc(func(ctx context.Context) error {
+		b.observer.shutdownBegin(nil)
+		return nil
+	})
+
+	initAndRunEachPriority = append(initAndRunEachPriority, b.startup.finish(b.logger), notifyApplicationReady)
+
 	return sequence(
-		chain(initAndRunEachPriority...),
-		forwardProcessErrors,
+		b.observer.observeErrors(sequence(
+			chain(initAndRunEachPriority...),
+			forwardProcessErrors,
+		)),
+		notifyShutdownBegin,
 		runFinalizers,
+		stopObservingHealth,
 	)
 }
 
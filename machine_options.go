@@ -1,5 +1,12 @@
 package process
 
+import (
+	"io"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
 type MachineConfigFunc func(*machineBuilder)
 
 // WithInjecter configures a machine builder instance to use the given inject hook.
@@ -11,3 +18,70 @@ func WithInjecter(injectHook Injecter) MachineConfigFunc {
 func WithHealth(health *Health) MachineConfigFunc {
 	return func(b *machineBuilder) { b.health = health }
 }
+
+// WithMetrics configures a machine builder instance to report lifecycle and health
+// observations to the given metrics instance.
+func WithMetrics(metrics Metrics) MachineConfigFunc {
+	return func(b *machineBuilder) { b.metrics = metrics }
+}
+
+// WithTracer configures a machine builder instance to create spans around the lifecycle
+// phases of each process with the given tracer.
+func WithTracer(tracer Tracer) MachineConfigFunc {
+	return func(b *machineBuilder) { b.tracer = tracer }
+}
+
+// WithLogger configures a machine builder instance to write the startup report to the
+// given logger.
+func WithLogger(logger Logger) MachineConfigFunc {
+	return func(b *machineBuilder) { b.logger = logger }
+}
+
+// WithInterceptor configures a machine builder instance to invoke the lifecycle hooks of
+// every process through the given interceptors. Interceptors configured on the machine
+// wrap those configured on an individual process.
+func WithInterceptor(interceptors ...Interceptor) MachineConfigFunc {
+	return func(b *machineBuilder) { b.interceptors = append(b.interceptors, interceptors...) }
+}
+
+// WithObserver configures a machine builder instance to notify the given observers of the
+// milestones of the process runner. Observers are notified in the order they are supplied.
+func WithObserver(observers ...Observer) MachineConfigFunc {
+	return func(b *machineBuilder) { b.observers = append(b.observers, observers...) }
+}
+
+// WithListeners configures a machine builder instance to make the given listener registry
+// available to the inject hook and to the hooks of each process via ListenersFromContext.
+func WithListeners(listeners *Listeners) MachineConfigFunc {
+	return func(b *machineBuilder) { b.listeners = listeners }
+}
+
+// WithUpgradeExecutable configures a machine builder instance to start the program at the
+// given path with the given arguments on upgrade. The default is to start the current
+// executable with the current arguments.
+func WithUpgradeExecutable(path string, args ...string) MachineConfigFunc {
+	return func(b *machineBuilder) { b.upgrader.path, b.upgrader.args = path, args }
+}
+
+// WithUpgradeReadyTimeout configures a machine builder instance with the maximum time the
+// program started by an upgrade may take to report readiness before it is killed and the
+// upgrade fails. The default timeout is one minute. A zero timeout disables the limit.
+func WithUpgradeReadyTimeout(timeout time.Duration) MachineConfigFunc {
+	return func(b *machineBuilder) { b.upgrader.readyTimeout = timeout }
+}
+
+func withUpgradeOutput(w io.Writer) MachineConfigFunc {
+	return func(b *machineBuilder) { b.upgrader.stdout, b.upgrader.stderr = w, w }
+}
+
+func withUpgradeClock(clock glock.Clock) MachineConfigFunc {
+	return func(b *machineBuilder) { b.upgrader.clock = clock }
+}
+
+func withUpgradeNotifySocket(socket string) MachineConfigFunc {
+	return func(b *machineBuilder) { b.upgrader.notifySocket = socket }
+}
+
+func withStartupClock(clock glock.Clock) MachineConfigFunc {
+	return func(b *machineBuilder) { b.startup.clock = clock }
+}
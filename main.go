@@ -0,0 +1,140 @@
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// Main runs the processes registered to the given container and exits the program once
+// they have exited. This function never returns.
+//
+// SIGINT and SIGTERM signal the processes to shut down; a second such signal forces the
+// program to exit immediately. Where supported, SIGHUP reloads each process implementing
+// Reloader, SIGUSR2 upgrades the program (see State.Upgrade), and SIGUSR1 writes the status
+// of each process and health component along with a dump of all goroutines.
+//
+// The program exits with status 0 if the processes exited cleanly, status 1 if an error
+// occurred, and (on Unix) status 128 plus the signal number if the exit was forced by a
+// signal.
+func Main(container *Container, configs ...MainConfigFunc) {
+	options := &mainOptions{
+		logger:     NilLogger,
+		dumpWriter: os.Stderr,
+		exit:       os.Exit,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	options.exit(runMain(container, options))
+}
+
+// runMain runs the processes registered to the given container while handling signals
+// and returns the exit code of the program.
+func runMain(container *Container, options *mainOptions) int {
+	signals := options.signals
+	if signals == nil {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, handledSignals()...)
+		defer signal.Stop(ch)
+		signals = ch
+	}
+
+	ctx := context.Background()
+	state := Run(ctx, container, options.machineConfigs...)
+
+	done := make(chan bool, 1)
+	go func() { done <- state.Wait(ctx) }()
+
+	shuttingDown := false
+	for {
+		select {
+		case ok := <-done:
+			if !ok {
+				for _, err := range state.Errors() {
+					options.logger.Error("%s", err)
+				}
+
+				return 1
+			}
+
+			return 0
+
+		case sig := <-signals:
+			switch {
+			case containsSignal(shutdownSignals, sig):
+				if shuttingDown {
+					options.logger.Error("Received second signal %s, forcing exit", sig)
+					return signalExitCode(sig)
+				}
+
+				shuttingDown = true
+				options.logger.Info("Received signal %s, shutting down", sig)
+				state.Shutdown(ctx)
+
+			case containsSignal(reloadSignals, sig):
+				options.logger.Info("Received signal %s, reloading", sig)
+
+				go func() {
+					if err := state.Reload(ctx); err != nil {
+						options.logger.Error("Failed to reload: %s", err)
+					}
+				}()
+
+			case containsSignal(upgradeSignals, sig):
+				options.logger.Info("Received signal %s, upgrading", sig)
+
+				go func() {
+					if err := state.Upgrade(ctx); err != nil {
+						options.logger.Error("Failed to upgrade: %s", err)
+					}
+				}()
+
+			case containsSignal(dumpSignals, sig):
+				options.logger.Info("Received signal %s, writing status", sig)
+
+				if err := writeDump(state, options); err != nil {
+					options.logger.Error("Failed to write status: %s", err)
+				}
+			}
+		}
+	}
+}
+
+// handledSignals returns every signal handled by Main.
+func handledSignals() []os.Signal {
+	var signals []os.Signal
+	for _, s := range [][]os.Signal{shutdownSignals, reloadSignals, upgradeSignals, dumpSignals} {
+		signals = append(signals, s...)
+	}
+
+	return signals
+}
+
+// writeDump writes the status of the application and a dump of all goroutines to the
+// configured dump writer.
+func writeDump(state *State, options *mainOptions) error {
+	if err := state.WriteStatus(options.dumpWriter); err != nil {
+		return err
+	}
+
+	if _, err := options.dumpWriter.Write([]byte("goroutines:\n")); err != nil {
+		return err
+	}
+
+	_, err := options.dumpWriter.Write(goroutineDump())
+	return err
+}
+
+// containsSignal returns true if the given signal is in the given list.
+func containsSignal(signals []os.Signal, sig os.Signal) bool {
+	for _, s := range signals {
+		if s == sig {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+//go:build js
+// +build js
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals that begin a graceful shutdown. A second shutdown
+// signal forces the application to exit.
+var shutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// reloadSignals are the signals that reload the processes of the application. No such
+// signal is available on this platform.
+var reloadSignals []os.Signal
+
+// dumpSignals are the signals that write the status of the application. No such
+// signal is available on this platform.
+var dumpSignals []os.Signal
+
+// upgradeSignals are the signals that upgrade the application. No such signal is
+// available on this platform.
+var upgradeSignals []os.Signal
+
+// signalExitCode returns the exit status of a program terminated by the given signal.
+func signalExitCode(sig os.Signal) int {
+	return 1
+}
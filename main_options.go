@@ -0,0 +1,42 @@
+package process
+
+import (
+	"io"
+	"os"
+)
+
+type mainOptions struct {
+	machineConfigs []MachineConfigFunc
+	logger         Logger
+	dumpWriter     io.Writer
+	signals        <-chan os.Signal
+	exit           func(code int)
+}
+
+type MainConfigFunc func(options *mainOptions)
+
+// WithMainMachineConfig configures Main to build the process runner with the given
+// machine configuration.
+func WithMainMachineConfig(configs ...MachineConfigFunc) MainConfigFunc {
+	return func(options *mainOptions) { options.machineConfigs = append(options.machineConfigs, configs...) }
+}
+
+// WithMainLogger configures Main to log received signals and their outcome to the
+// given logger.
+func WithMainLogger(logger Logger) MainConfigFunc {
+	return func(options *mainOptions) { options.logger = logger }
+}
+
+// WithMainDumpWriter configures Main to write the status and goroutine dump requested
+// by SIGUSR1 to the given writer. The default writer is standard error.
+func WithMainDumpWriter(w io.Writer) MainConfigFunc {
+	return func(options *mainOptions) { options.dumpWriter = w }
+}
+
+func withMainSignals(signals <-chan os.Signal) MainConfigFunc {
+	return func(options *mainOptions) { options.signals = signals }
+}
+
+func withMainExit(exit func(code int)) MainConfigFunc {
+	return func(options *mainOptions) { options.exit = exit }
+}
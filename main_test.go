@@ -0,0 +1,120 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainShutdownSignal(t *testing.T) {
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	signals := make(chan os.Signal, 1)
+	exit := runMainAsync(builder.Build(), signals)
+
+	<-started
+	signals <- syscall.SIGTERM
+	assert.Equal(t, 0, <-exit)
+}
+
+func TestMainForcedExit(t *testing.T) {
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	stopHook, stopping := newBlockingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	process.StopFunc.SetDefaultHook(stopHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	signals := make(chan os.Signal, 1)
+	exit := runMainAsync(builder.Build(), signals)
+
+	<-started
+	signals <- syscall.SIGTERM
+	<-stopping
+	signals <- syscall.SIGINT
+	assert.Equal(t, signalExitCode(syscall.SIGINT), <-exit)
+}
+
+func TestMainError(t *testing.T) {
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	process.RunFunc.SetDefaultReturn(testErr1)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	exit := runMainAsync(builder.Build(), make(chan os.Signal))
+	assert.Equal(t, 1, <-exit)
+}
+
+type reloadingProcess struct {
+	*MockMaximumProcess
+	reloads chan struct{}
+}
+
+func (p *reloadingProcess) Reload(ctx context.Context) error {
+	p.reloads <- struct{}{}
+	return nil
+}
+
+func TestMainReloadSignal(t *testing.T) {
+	if len(reloadSignals) == 0 {
+		t.Skip("no reload signal on this platform")
+	}
+
+	builder := NewContainerBuilder()
+	process := &reloadingProcess{MockMaximumProcess: NewMockMaximumProcess(), reloads: make(chan struct{}, 1)}
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	signals := make(chan os.Signal, 1)
+	exit := runMainAsync(builder.Build(), signals)
+
+	<-started
+	signals <- reloadSignals[0]
+	<-process.reloads
+	signals <- syscall.SIGTERM
+	assert.Equal(t, 0, <-exit)
+}
+
+func TestMainDumpSignal(t *testing.T) {
+	if len(dumpSignals) == 0 {
+		t.Skip("no dump signal on this platform")
+	}
+
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	var buf bytes.Buffer
+	signals := make(chan os.Signal)
+	exit := runMainAsync(builder.Build(), signals, WithMainDumpWriter(&buf))
+
+	<-started
+	signals <- dumpSignals[0]
+	signals <- syscall.SIGTERM
+	require.Equal(t, 0, <-exit)
+
+	assert.Contains(t, buf.String(), "processes:\n  p: phase=run restarts=0 last_error=\nhealth: healthy=true\n")
+	assert.Contains(t, buf.String(), "goroutines:\ngoroutine ")
+}
+
+// runMainAsync invokes Main with the given signal channel and returns a channel that
+// receives the exit code.
+func runMainAsync(container *Container, signals <-chan os.Signal, configs ...MainConfigFunc) <-chan int {
+	exit := make(chan int, 1)
+	configs = append(configs, withMainSignals(signals), withMainExit(func(code int) { exit <- code }))
+	go Main(container, configs...)
+	return exit
+}
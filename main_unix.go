@@ -0,0 +1,32 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals that begin a graceful shutdown. A second shutdown
+// signal forces the application to exit.
+var shutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// reloadSignals are the signals that reload the processes of the application.
+var reloadSignals = []os.Signal{syscall.SIGHUP}
+
+// dumpSignals are the signals that write the status of the application.
+var dumpSignals = []os.Signal{syscall.SIGUSR1}
+
+// upgradeSignals are the signals that upgrade the application.
+var upgradeSignals = []os.Signal{syscall.SIGUSR2}
+
+// signalExitCode returns the conventional exit status of a program terminated by the
+// given signal.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+
+	return 1
+}
@@ -14,40 +14,72 @@ import (
 // receiver's methods are only called once and not called from an invalid state
 // (e.g. Run called before Init or after a failed Init).
 type Meta struct {
-	wrapped     interface{}
-	options     *metaOptions
-	logger      Logger
-	mu          sync.Mutex
-	initialized bool
-	running     bool
-	stopping    bool
-	stopped     chan struct{}
+	wrapped              interface{}
+	options              *metaOptions
+	logger               DebugLogger
+	scopedHealth         *ScopedHealth
+	machineHealth        *ScopedHealth
+	healthCheckComponent *HealthComponentStatus
+	errorReporter        func(err error)
+	interceptors         []Interceptor
+	lifecycle            lifecycle
+	metrics              Metrics
+	tracer               Tracer
+	timeline             *timeline
+	timelineLane         timelineLane
+	listeners            *Listeners
+	mu                   sync.Mutex
+	initialized          bool
+	running              bool
+	stopping             bool
+	stopped              chan struct{}
 }
 
 var defaultClock = glock.NewRealClock()
 
+// defaultHealthCheckInterval is the interval between health checks for processes
+// that implement HealthChecker but do not configure an explicit interval.
+const defaultHealthCheckInterval = time.Second * 5
+
 func newMeta(wrapped interface{}, configs ...MetaConfigFunc) *Meta {
 	options := &metaOptions{
-		health:        NewHealth(),
-		contextFilter: func(ctx context.Context) context.Context { return ctx },
-		logger:        NilLogger,
-		initClock:     defaultClock,
-		startupClock:  defaultClock,
-		stopClock:     defaultClock,
-		shutdownClock: defaultClock,
-		finalizeClock: defaultClock,
+		health:                      NewHealth(),
+		contextFilter:               func(ctx context.Context) context.Context { return ctx },
+		healthCheckInterval:         defaultHealthCheckInterval,
+		healthCheckFailureThreshold: 1,
+		healthCheckSuccessThreshold: 1,
+		logger:                      NilLogger,
+		initClock:                   defaultClock,
+		startupClock:                defaultClock,
+		stopClock:                   defaultClock,
+		shutdownClock:               defaultClock,
+		finalizeClock:               defaultClock,
+		healthCheckClock:            defaultClock,
+		watchdogClock:               defaultClock,
+		lifecycleClock:              defaultClock,
 	}
 
 	for _, f := range configs {
 		f(options)
 	}
 
-	return &Meta{
-		wrapped: wrapped,
-		options: options,
-		logger:  options.logger.WithFields(options.metadata),
-		stopped: make(chan struct{}),
+	meta := &Meta{
+		wrapped:      wrapped,
+		options:      options,
+		logger:       newLeveledLogger(options.logger.WithFields(options.metadata), options.logLevel),
+		scopedHealth: newScopedHealth(options.health),
+		interceptors: options.interceptors,
+		metrics:      NilMetrics,
+		tracer:       NilTracer,
+		stopped:      make(chan struct{}),
+	}
+	meta.machineHealth = meta.scopedHealth
+
+	if _, ok := meta.healthChecker(); ok {
+		options.healthKeys = append(options.healthKeys, healthCheckKey{meta: meta})
 	}
+
+	return meta
 }
 
 // Wrapped returns the underlying receiver.
@@ -69,6 +101,60 @@ func (m *Meta) Metadata() map[string]interface{} {
 	return m.options.metadata
 }
 
+// Priority returns the process's configured priority.
+func (m *Meta) Priority() int {
+	return m.options.priority
+}
+
+// Options returns a snapshot of the process's configuration. The health keys include
+// the key of the health component registered for processes implementing HealthChecker,
+// and the health check interval is the interval in effect (see WithMetaHealthCheckInterval).
+func (m *Meta) Options() MetaOptions {
+	healthKeys := make([]interface{}, len(m.options.healthKeys))
+	copy(healthKeys, m.options.healthKeys)
+
+	return MetaOptions{
+		Name:                        m.options.name,
+		Priority:                    m.options.priority,
+		Metadata:                    copyMetadata(m.options.metadata),
+		HealthKeys:                  healthKeys,
+		AllowEarlyExit:              m.options.allowEarlyExit,
+		InitTimeout:                 m.options.initTimeout,
+		StartupTimeout:              m.options.startupTimeout,
+		StopTimeout:                 m.options.stopTimeout,
+		ShutdownTimeout:             m.options.shutdownTimeout,
+		FinalizeTimeout:             m.options.finalizeTimeout,
+		HealthCheckInterval:         m.healthCheckInterval(),
+		HealthCheckTimeout:          m.options.healthCheckTimeout,
+		HealthCheckFailureThreshold: m.options.healthCheckFailureThreshold,
+		HealthCheckSuccessThreshold: m.options.healthCheckSuccessThreshold,
+		WatchdogTimeout:             m.options.watchdogTimeout,
+		WatchdogRestart:             m.options.watchdogRestart,
+		LogLevel:                    m.options.logLevel,
+	}
+}
+
+// copyMetadata returns a shallow copy of the given metadata.
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// Health returns a handle to the process's health instance. Components registered
+// through this handle are unregistered once the process is stopped or finalized. The
+// same handle is available to the wrapped value's hooks via ScopedHealthFromContext.
+func (m *Meta) Health() *ScopedHealth {
+	return m.scopedHealth
+}
+
 // Init invokes the wrapped value's Init method.
 //
 // A timeout error will be returned if the invocation does not unblock within the configured
@@ -83,10 +169,14 @@ func (m *Meta) Init(ctx context.Context) (err error) {
 	}()
 
 	if initializer, ok := m.wrapped.(Initializer); ok {
-		return m.makeRunWithTimeout(ctx, "init", initializer.Init, m.options.initClock, m.options.initTimeout)
+		if err := m.traced(ctx, PhaseInit, func(ctx context.Context) error {
+			return m.makeRunWithTimeout(ctx, PhaseInit, initializer.Init, m.options.initClock, m.options.initTimeout)
+		}); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return m.registerHealthCheck()
 }
 
 // Run invokes the wrapped value's Run method.
@@ -109,7 +199,9 @@ func (m *Meta) Run(ctx context.Context) error {
 			m.running = false
 		}()
 
-		return m.run(ctx, runner)
+		err := m.run(ctx, runner)
+		m.recordError(err)
+		return err
 	}
 
 	return nil
@@ -128,13 +220,36 @@ func (m *Meta) shouldRun() bool {
 }
 
 func (m *Meta) run(ctx context.Context, runner Runner) error {
+	for {
+		err := m.runOnce(ctx, runner)
+		if !isRestartError(err) {
+			return err
+		}
+
+		m.logger.Warning("%s: restarting after watchdog timeout", m.Name())
+	}
+}
+
+func (m *Meta) runOnce(ctx context.Context, runner Runner) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	result := runAsync(ctx, func(ctx context.Context) error {
-		return m.makeRunWithTimeout(ctx, "run", runner.Run, nil, 0)
+	start := m.options.lifecycleClock.Now()
+
+	// The run span covers the time until the process becomes healthy
+	spanCtx, span := m.startSpan(ctx, PhaseRun)
+	var endSpanOnce sync.Once
+	endSpan := func(err error) { endSpanOnce.Do(func() { span.End(err) }) }
+	defer func() { endSpan(err) }()
+
+	result := runAsync(spanCtx, func(ctx context.Context) error {
+		return m.makeRunWithTimeout(ctx, PhaseRun, runner.Run, nil, 0)
 	})
 
+	if checker, ok := m.healthChecker(); ok {
+		go m.runHealthChecks(ctx, checker)
+	}
+
 	healthStatusChannel, err := m.watchHealthStatus()
 	if err != nil {
 		return err
@@ -145,11 +260,24 @@ func (m *Meta) run(ctx context.Context, runner Runner) error {
 		if !v {
 			return ErrStartupTimeout
 		}
+		endSpan(nil)
+		if len(m.options.healthKeys) > 0 {
+			m.recordTimeline("run until healthy", start, m.options.lifecycleClock.Now(), nil)
+		}
+
+		watchdog, err := m.watchWatchdog(ctx)
+		if err != nil {
+			return err
+		}
 
 		select {
 		case err := <-result:
 			return m.handleResult(ctx, err)
 
+		case err := <-watchdog:
+			cancel()
+			return m.handleWatchdog(err, result)
+
 		case <-m.stopped:
 			cancel()
 		}
@@ -170,12 +298,68 @@ func (m *Meta) run(ctx context.Context, runner Runner) error {
 	}
 }
 
+// handleWatchdog is invoked after the watchdog of a running process fires and the context
+// of its Run method has been canceled. If the process is not restarted, the error is sent to
+// the process runner immediately so that shutdown is not blocked on a wedged Run method.
+// The Run method is then given a bounded amount of time to return. The process is restarted
+// only if it is configured to do so and the previous invocation of Run has returned.
+//
+// The returned error is a restart error if the process should be run again, nil if the
+// error has already been sent to the process runner, and the watchdog error otherwise.
+func (m *Meta) handleWatchdog(err *WatchdogError, result <-chan error) error {
+	restart := m.options.watchdogRestart && !m.isStopping()
+
+	reported := false
+	if !restart && m.errorReporter != nil {
+		m.recordError(err)
+		m.reportError(err)
+		reported = true
+	}
+
+	returned := false
+	select {
+	case <-result:
+		returned = true
+
+	case <-m.watchdogGraceTimeout():
+		m.logger.Error("%s: %s", m.Name(), ErrShutdownTimeout)
+
+	case <-m.stopped:
+	}
+
+	if restart && returned && !m.isStopping() {
+		err.Restarted = true
+		m.recordError(err)
+		m.recordRestart()
+		m.reportError(err)
+		return err
+	}
+
+	if reported {
+		return nil
+	}
+
+	return err
+}
+
+// watchdogGraceTimeout returns a channel that receives a value once the Run method of a
+// process whose watchdog has fired should be abandoned. This is the shutdown timeout if
+// one is configured, and the watchdog timeout otherwise.
+func (m *Meta) watchdogGraceTimeout() <-chan time.Time {
+	timeout := m.options.shutdownTimeout
+	if timeout <= 0 {
+		timeout = m.options.watchdogTimeout
+	}
+
+	return m.options.watchdogClock.After(timeout)
+}
+
 // watchHealthStatus returns a channel that will receive the value true when the process
 // becomes healthy, or false after the startup timeout has elapsed. If there are no health
 // keys registered to this process then a nil channel is returned. Note that reading from
 // a nil channel blocks forever.
 func (m *Meta) watchHealthStatus() (chan bool, error) {
-	components, err := m.options.health.GetAll(m.options.healthKeys...)
+	components, err := m.healthComponents()
 	if err != nil {
 		return nil, err
 	}
@@ -191,29 +375,21 @@ func (m *Meta) watchHealthComponentStatus(components []*HealthComponentStatus) c
 	go func() {
 		defer close(timedOut)
 
-		ch, cancel := m.options.health.Subscribe()
+		ch, cancel := m.subscribeHealthChanges()
 		defer cancel()
 
-		for {
+		timeout := afterZeroUnbounded(m.options.startupClock, m.options.startupTimeout)
+
+		for !healthy(components) {
 			select {
-			case <-afterZeroUnbounded(m.options.startupClock, m.options.startupTimeout):
+			case <-timeout:
 				return
 
 			case <-ch:
-				healthy := true
-				for _, component := range components {
-					if !component.Healthy() {
-						healthy = false
-						break
-					}
-				}
-
-				if healthy {
-					timedOut <- true
-					return
-				}
 			}
 		}
+
+		timedOut <- true
 	}()
 
 	return timedOut
@@ -223,17 +399,30 @@ func (m *Meta) watchHealthComponentStatus(components []*HealthComponentStatus) c
 // This will mark the meta instance as stopping, and determine the appropriate error
 // value.
 func (m *Meta) handleResult(ctx context.Context, err error) error {
-	m.mu.Lock()
-	stopping := m.stopping
-	m.mu.Unlock()
-
-	if err == nil && !stopping && !m.options.allowEarlyExit {
+	if err == nil && !m.isStopping() && !m.options.allowEarlyExit {
 		err = ErrUnexpectedReturn
 	}
 
 	return ignoreContextError(ctx, err)
 }
 
+// isStopping returns true if the meta instance's Stop method has been called.
+func (m *Meta) isStopping() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stopping
+}
+
+// reportError sends the given error to the process runner without ending the
+// invocation of the Run method. This method no-ops if the meta instance is not
+// being run by a process runner.
+func (m *Meta) reportError(err error) {
+	if m.errorReporter != nil {
+		m.errorReporter(err)
+	}
+}
+
 // ignoreContextError returns nil if the given error is equal to the given context's
 // underlying error and the given error otherwise. The given error may be wrapped.
 func ignoreContextError(ctx context.Context, err error) error {
@@ -258,9 +447,13 @@ func (m *Meta) Stop(ctx context.Context) error {
 	}
 
 	defer close(m.stopped)
+	defer m.scopedHealth.unregisterAll()
+	defer m.machineHealth.unregisterAll()
 
 	if stopper, ok := m.wrapped.(Stopper); ok {
-		return m.makeRunWithTimeout(ctx, "stop", stopper.Stop, m.options.stopClock, m.options.stopTimeout)
+		return m.traced(ctx, PhaseStop, func(ctx context.Context) error {
+			return m.makeRunWithTimeout(ctx, PhaseStop, stopper.Stop, m.options.stopClock, m.options.stopTimeout)
+		})
 	}
 
 	return nil
@@ -285,8 +478,17 @@ func (m *Meta) shouldRunStop() bool {
 //
 // This method will no-op if the meta instance was not initialized.
 func (m *Meta) Finalize(ctx context.Context) error {
-	if finalizer, ok := m.wrapped.(Finalizer); ok && m.shouldRunFinalize() {
-		return m.makeRunWithTimeout(ctx, "finalize", finalizer.Finalize, m.options.finalizeClock, m.options.finalizeTimeout)
+	if !m.shouldRunFinalize() {
+		return nil
+	}
+
+	defer m.scopedHealth.unregisterAll()
+	defer m.machineHealth.unregisterAll()
+
+	if finalizer, ok := m.wrapped.(Finalizer); ok {
+		return m.traced(ctx, PhaseFinalize, func(ctx context.Context) error {
+			return m.makeRunWithTimeout(ctx, PhaseFinalize, finalizer.Finalize, m.options.finalizeClock, m.options.finalizeTimeout)
+		})
 	}
 
 	return nil
@@ -298,14 +500,17 @@ func (m *Meta) shouldRunFinalize() bool {
 	return m.initialized
 }
 
-func (m *Meta) makeRunWithTimeout(ctx context.Context, opName string, fn func(ctx context.Context) error, clock glock.Clock, timeout time.Duration) error {
-	m.logger.Info("%s: %s starting", m.Name(), opName)
+func (m *Meta) makeRunWithTimeout(ctx context.Context, opName string, fn func(ctx context.Context) error, clock glock.Clock, timeout time.Duration) (err error) {
+	start := m.beginPhase(opName)
+	defer func(ctx context.Context) { m.endPhase(opName, start, ignoreContextError(ctx, err)) }(ctx)
+
+	m.logger.Debug("%s: %s starting", m.Name(), opName)
 
-	ctx, cancel := context.WithCancel(m.options.contextFilter(ctx))
+	ctx, cancel := context.WithCancel(m.hookContext(ctx))
 	defer cancel()
 
 	select {
-	case err := <-toStreamErrorFunc(fn)(ctx):
+	case err := <-toStreamErrorFunc(m.intercept(opName, fn))(ctx):
 		if err != nil {
 			return &opError{
 				source:   err,
@@ -315,7 +520,7 @@ func (m *Meta) makeRunWithTimeout(ctx context.Context, opName string, fn func(ct
 			}
 		}
 
-		m.logger.Info("%s: %s finished", m.Name(), opName)
+		m.logger.Debug("%s: %s finished", m.Name(), opName)
 		return nil
 
 	case <-afterZeroUnbounded(clock, timeout):
@@ -328,6 +533,20 @@ func (m *Meta) makeRunWithTimeout(ctx context.Context, opName string, fn func(ct
 	}
 }
 
+// hookContext returns the context passed to the wrapped value's hooks. The returned
+// context carries the process's logger, its scoped health handle and, if the process is
+// being run by a process runner configured with listeners, the listener registry.
+func (m *Meta) hookContext(ctx context.Context) context.Context {
+	ctx = contextWithLogger(ctx, m.logger)
+	ctx = contextWithScopedHealth(ctx, m.scopedHealth)
+	ctx = contextWithShutdownTimeout(ctx, m.options.shutdownTimeout)
+	if m.listeners != nil {
+		ctx = ContextWithListeners(ctx, m.listeners)
+	}
+
+	return m.options.contextFilter(ctx)
+}
+
 // afterZeroUnbounded returns a channel that will receive a value after the given
 // timeout. If the given timeout is zero, a nil channel will be returned. Note that
 // reading from a nil channel blocks forever.
@@ -8,28 +8,60 @@ import (
 )
 
 type metaOptions struct {
-	health          *Health
-	healthKeys      []interface{}
-	contextFilter   func(ctx context.Context) context.Context
-	name            string
-	metadata        map[string]interface{}
-	priority        int
-	allowEarlyExit  bool
-	initTimeout     time.Duration
-	startupTimeout  time.Duration
-	stopTimeout     time.Duration
-	shutdownTimeout time.Duration
-	finalizeTimeout time.Duration
-	logger          Logger
-	initClock       glock.Clock
-	startupClock    glock.Clock
-	stopClock       glock.Clock
-	shutdownClock   glock.Clock
-	finalizeClock   glock.Clock
+	health                      *Health
+	healthKeys                  []interface{}
+	contextFilter               func(ctx context.Context) context.Context
+	interceptors                []Interceptor
+	name                        string
+	metadata                    map[string]interface{}
+	priority                    int
+	allowEarlyExit              bool
+	initTimeout                 time.Duration
+	startupTimeout              time.Duration
+	stopTimeout                 time.Duration
+	shutdownTimeout             time.Duration
+	finalizeTimeout             time.Duration
+	healthCheckInterval         time.Duration
+	healthCheckTimeout          time.Duration
+	healthCheckFailureThreshold int
+	healthCheckSuccessThreshold int
+	watchdogTimeout             time.Duration
+	watchdogRestart             bool
+	logger                      Logger
+	logLevel                    LogLevel
+	initClock                   glock.Clock
+	startupClock                glock.Clock
+	stopClock                   glock.Clock
+	shutdownClock               glock.Clock
+	finalizeClock               glock.Clock
+	healthCheckClock            glock.Clock
+	watchdogClock               glock.Clock
+	lifecycleClock              glock.Clock
 }
 
 type MetaConfigFunc func(meta *metaOptions)
 
+// MetaOptions is a snapshot of the configuration of a process.
+type MetaOptions struct {
+	Name                        string
+	Priority                    int
+	Metadata                    map[string]interface{}
+	HealthKeys                  []interface{}
+	AllowEarlyExit              bool
+	InitTimeout                 time.Duration
+	StartupTimeout              time.Duration
+	StopTimeout                 time.Duration
+	ShutdownTimeout             time.Duration
+	FinalizeTimeout             time.Duration
+	HealthCheckInterval         time.Duration
+	HealthCheckTimeout          time.Duration
+	HealthCheckFailureThreshold int
+	HealthCheckSuccessThreshold int
+	WatchdogTimeout             time.Duration
+	WatchdogRestart             bool
+	LogLevel                    LogLevel
+}
+
 // WithMetaHealth configures a Meta instance to use the given health instance.
 func WithMetaHealth(health *Health) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.health = health }
@@ -48,6 +80,13 @@ func WithMetaContext(f func(ctx context.Context) context.Context) MetaConfigFunc
 	return func(meta *metaOptions) { meta.contextFilter = f }
 }
 
+// WithMetaInterceptor configures a Meta instance to invoke the wrapped value's underlying
+// Init, Run, Stop, or Finalize methods as well as the inject hook through the given
+// interceptors. Interceptors supplied earlier wrap those supplied later.
+func WithMetaInterceptor(interceptors ...Interceptor) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.interceptors = append(meta.interceptors, interceptors...) }
+}
+
 // WithMetaName tags a Meta instance with the given name.
 func WithMetaName(name string) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.name = name }
@@ -102,11 +141,59 @@ func WithMetaFinalizeTimeout(timeout time.Duration) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.finalizeTimeout = timeout }
 }
 
+// WithMetaHealthCheckInterval configures a Meta instance with the given interval
+// between invocations of the wrapped value's CheckHealth method. A non-positive
+// interval is replaced by the default interval of five seconds.
+func WithMetaHealthCheckInterval(interval time.Duration) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.healthCheckInterval = interval }
+}
+
+// WithMetaHealthCheckTimeout configures a Meta instance with the given timeout for
+// each invocation of the wrapped value's CheckHealth method. A check that does not
+// unblock within the timeout is treated as a failure.
+func WithMetaHealthCheckTimeout(timeout time.Duration) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.healthCheckTimeout = timeout }
+}
+
+// WithMetaHealthCheckFailureThreshold configures a Meta instance with the number of
+// consecutive failed health checks required to mark a healthy process as unhealthy.
+func WithMetaHealthCheckFailureThreshold(threshold int) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.healthCheckFailureThreshold = threshold }
+}
+
+// WithMetaHealthCheckSuccessThreshold configures a Meta instance with the number of
+// consecutive successful health checks required to mark an unhealthy process as healthy.
+func WithMetaHealthCheckSuccessThreshold(threshold int) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.healthCheckSuccessThreshold = threshold }
+}
+
+// WithMetaWatchdogTimeout configures a Meta instance with the given timeout for the
+// time the process's health components may remain unhealthy after the process has
+// become healthy. A stalled process is treated as a failure.
+func WithMetaWatchdogTimeout(timeout time.Duration) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.watchdogTimeout = timeout }
+}
+
+// WithMetaWatchdogRestart sets the flag that determines if a process stalled beyond its
+// watchdog timeout is restarted. The default behavior is to shut down the application.
+// A process is restarted only once its previous Run method returns, which it must do
+// within the shutdown timeout, or the watchdog timeout if no shutdown timeout is set.
+func WithMetaWatchdogRestart(restart bool) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.watchdogRestart = restart }
+}
+
 // WithMetaLogger configures a Meta instance with the given logger instance.
 func WithMetaLogger(logger Logger) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.logger = logger }
 }
 
+// WithMetaLogLevel sets the minimum level of messages written to the process's logger.
+// Messages below this level are discarded. The default behavior is to write messages
+// of every level.
+func WithMetaLogLevel(level LogLevel) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.logLevel = level }
+}
+
 func withMetaInitClock(clock glock.Clock) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.initClock = clock }
 }
@@ -126,3 +213,15 @@ func withMetaShutdownClock(clock glock.Clock) MetaConfigFunc {
 func withMetaFinalizeClock(clock glock.Clock) MetaConfigFunc {
 	return func(meta *metaOptions) { meta.finalizeClock = clock }
 }
+
+func withMetaHealthCheckClock(clock glock.Clock) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.healthCheckClock = clock }
+}
+
+func withMetaWatchdogClock(clock glock.Clock) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.watchdogClock = clock }
+}
+
+func withMetaLifecycleClock(clock glock.Clock) MetaConfigFunc {
+	return func(meta *metaOptions) { meta.lifecycleClock = clock }
+}
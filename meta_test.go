@@ -3,6 +3,7 @@ package process
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -18,6 +19,30 @@ func TestMetaName(t *testing.T) {
 	assert.Equal(t, "<unnamed *process.MockMaximumProcess>", meta.Name())
 }
 
+func TestMetaOptions(t *testing.T) {
+	meta := newMeta(
+		NewMockMaximumProcess(),
+		WithMetaName("a"),
+		WithMetaPriority(3),
+		WithMetaHealthKey("x", "y"),
+		WithMetaInitTimeout(time.Second),
+		WithMetadata(map[string]interface{}{"k": "v"}),
+	)
+
+	options := meta.Options()
+	assert.Equal(t, 3, meta.Priority())
+	assert.Equal(t, "a", options.Name)
+	assert.Equal(t, 3, options.Priority)
+	assert.Equal(t, []interface{}{"x", "y"}, options.HealthKeys)
+	assert.Equal(t, time.Second, options.InitTimeout)
+
+	options.HealthKeys[0] = "z"
+	assert.Equal(t, []interface{}{"x", "y"}, meta.Options().HealthKeys)
+
+	options.Metadata["k"] = "w"
+	assert.Equal(t, map[string]interface{}{"k": "v"}, meta.Options().Metadata)
+}
+
 func TestMetaInit(t *testing.T) {
 	wrapped := NewMockMaximumProcess()
 	meta := newMeta(wrapped)
@@ -47,6 +72,66 @@ func TestMetaInitTimeout(t *testing.T) {
 	assertChannelContents(t, readErrorChannel(results), seq(errors.New("test-service: init timeout")))
 }
 
+func TestMetaLogLevel(t *testing.T) {
+	logger := &testLogger{}
+	meta := newMeta(NewMockMaximumProcess(), WithMetaName("test-service"), WithMetaLogger(logger))
+
+	assert.Nil(t, meta.Init(context.Background()))
+	assert.Equal(t, []string{"DEBUG: test-service: init starting", "DEBUG: test-service: init finished"}, logger.snapshot())
+
+	logger = &testLogger{}
+	meta = newMeta(NewMockMaximumProcess(), WithMetaName("test-service"), WithMetaLogger(logger), WithMetaLogLevel(LogLevelInfo))
+
+	assert.Nil(t, meta.Init(context.Background()))
+	assert.Empty(t, logger.snapshot())
+}
+
+func TestMetaInterceptor(t *testing.T) {
+	type contextKey struct{}
+	var calls []string
+
+	interceptor := func(name string) Interceptor {
+		return func(ctx context.Context, info HookInfo, next func(ctx context.Context) error) error {
+			calls = append(calls, fmt.Sprintf("%s.%s.%s.before", name, info.Name, info.Phase))
+			err := next(context.WithValue(ctx, contextKey{}, name))
+			calls = append(calls, fmt.Sprintf("%s.%s.%s.after", name, info.Name, info.Phase))
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		}
+	}
+
+	wrapped := NewMockMaximumProcess()
+	wrapped.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		calls = append(calls, fmt.Sprintf("init.%s", ctx.Value(contextKey{})))
+		return testErr1
+	})
+	meta := newMeta(wrapped, WithMetaName("test-service"), WithMetaInterceptor(interceptor("a")), WithMetaInterceptor(interceptor("b")))
+
+	err := meta.Init(context.Background())
+	assert.EqualError(t, err, "test-service: init failed (a: b: oops1)")
+	assert.True(t, errors.Is(err, testErr1))
+	assert.Equal(t, []string{
+		"a.test-service.init.before",
+		"b.test-service.init.before",
+		"init.b",
+		"b.test-service.init.after",
+		"a.test-service.init.after",
+	}, calls)
+}
+
+func TestMetaInterceptorMetadata(t *testing.T) {
+	interceptor := func(ctx context.Context, info HookInfo, next func(ctx context.Context) error) error {
+		info.Metadata["k"] = "w"
+		return next(ctx)
+	}
+
+	meta := newMeta(NewMockMaximumProcess(), WithMetadata(map[string]interface{}{"k": "v"}), WithMetaInterceptor(interceptor))
+	assert.Nil(t, meta.Init(context.Background()))
+	assert.Equal(t, map[string]interface{}{"k": "v"}, meta.Metadata())
+}
+
 func TestMetaRun(t *testing.T) {
 	wrapped := NewMockMaximumProcess()
 	runHook, started := newSingalingSingleErrorFunc()
@@ -251,3 +336,25 @@ func TestMetaFinalizeCalledUninitialized(t *testing.T) {
 	assert.Nil(t, meta.Finalize(context.Background()))
 	mockassert.NotCalled(t, wrapped.FinalizeFunc)
 }
+
+func TestMetaScopedHealth(t *testing.T) {
+	health := NewHealth()
+	wrapped := NewMockMaximumProcess()
+	wrapped.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		_, err := ScopedHealthFromContext(ctx).Register("test")
+		return err
+	})
+
+	for i := 0; i < 2; i++ {
+		meta := newMeta(wrapped, WithMetaHealth(health))
+		assert.Nil(t, meta.Init(context.Background()))
+
+		_, ok := health.Get("test")
+		assert.True(t, ok)
+
+		assert.Nil(t, meta.Finalize(context.Background()))
+
+		_, ok = health.Get("test")
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,18 @@
+package process
+
+import "time"
+
+// Metrics receives observations about the lifecycle of processes and the status of
+// health components from the process runner.
+type Metrics interface {
+	// PhaseDuration is invoked after each lifecycle phase of the named process
+	// completes with the duration of the phase and the error it returned, if any.
+	PhaseDuration(name, phase string, duration time.Duration, err error)
+
+	// Restart is invoked each time the named process is restarted.
+	Restart(name string)
+
+	// HealthTransition is invoked each time the health component registered to
+	// the given key changes status.
+	HealthTransition(key interface{}, healthy bool)
+}
@@ -0,0 +1,11 @@
+package process
+
+import "time"
+
+type nilMetrics struct{}
+
+var NilMetrics Metrics = nilMetrics{}
+
+func (m nilMetrics) PhaseDuration(string, string, time.Duration, error) {}
+func (m nilMetrics) Restart(string)                                     {}
+func (m nilMetrics) HealthTransition(interface{}, bool)                 {}
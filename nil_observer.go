@@ -0,0 +1,11 @@
+package process
+
+type nilObserver struct{}
+
+var NilObserver Observer = nilObserver{}
+
+func (o nilObserver) OnPriorityStarted(int)      {}
+func (o nilObserver) OnPriorityHealthy(int)      {}
+func (o nilObserver) OnApplicationReady()        {}
+func (o nilObserver) OnShutdownBegin(error)      {}
+func (o nilObserver) OnShutdownComplete([]error) {}
@@ -0,0 +1,104 @@
+package process
+
+import (
+	"context"
+	"sync"
+)
+
+// Observer receives notifications about the milestones of the process runner.
+type Observer interface {
+	// OnPriorityStarted is invoked before the processes registered to the given
+	// priority are injected and initialized.
+	OnPriorityStarted(priority int)
+
+	// OnPriorityHealthy is invoked once the processes registered to the given
+	// priority have started and their health components are healthy.
+	OnPriorityHealthy(priority int)
+
+	// OnApplicationReady is invoked once the processes registered to every priority
+	// have started and become healthy.
+	OnApplicationReady()
+
+	// OnShutdownBegin is invoked once when the application begins to shut down. The
+	// reason is the error that caused the shutdown, or nil if shutdown was requested
+	// explicitly or every process exited.
+	OnShutdownBegin(reason error)
+
+	// OnShutdownComplete is invoked once every process has exited and been finalized
+	// with the errors that occurred during execution.
+	OnShutdownComplete(errors []error)
+}
+
+// multiObserver invokes the callbacks of each of a list of observers in order.
+type multiObserver []Observer
+
+func (o multiObserver) OnPriorityStarted(priority int) {
+	for _, observer := range o {
+		observer.OnPriorityStarted(priority)
+	}
+}
+
+func (o multiObserver) OnPriorityHealthy(priority int) {
+	for _, observer := range o {
+		observer.OnPriorityHealthy(priority)
+	}
+}
+
+func (o multiObserver) OnApplicationReady() {
+	for _, observer := range o {
+		observer.OnApplicationReady()
+	}
+}
+
+func (o multiObserver) OnShutdownBegin(reason error) {
+	for _, observer := range o {
+		observer.OnShutdownBegin(reason)
+	}
+}
+
+func (o multiObserver) OnShutdownComplete(errors []error) {
+	for _, observer := range o {
+		observer.OnShutdownComplete(errors)
+	}
+}
+
+// observerNotifier invokes the callbacks of an observer, ensuring that each shutdown
+// callback is invoked at most once.
+type observerNotifier struct {
+	Observer
+	shutdownBeginOnce    sync.Once
+	shutdownCompleteOnce sync.Once
+}
+
+func newObserverNotifier(observer Observer) *observerNotifier {
+	return &observerNotifier{Observer: observer}
+}
+
+// shutdownBegin invokes the observer's OnShutdownBegin callback if it has not already
+// been invoked.
+func (n *observerNotifier) shutdownBegin(reason error) {
+	n.shutdownBeginOnce.Do(func() { n.OnShutdownBegin(reason) })
+}
+
+// shutdownComplete invokes the observer's OnShutdownComplete callback if it has not
+// already been invoked.
+func (n *observerNotifier) shutdownComplete(errors []error) {
+	n.shutdownCompleteOnce.Do(func() { n.OnShutdownComplete(errors) })
+}
+
+// observeErrors creates a function that invokes the given function and notifies the
+// observer that the application is shutting down when the function emits an error that
+// does not restart a process.
+func (n *observerNotifier) observeErrors(fn streamErrorFunc) streamErrorFunc {
+	return func(ctx context.Context) <-chan error {
+		return withErrors(func(errs chan<- error) {
+			for err := range fn(ctx) {
+				if !isRestartError(err) {
+					n.shutdownBegin(err)
+				}
+
+				errs <- err
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PIDFile is an initializer that writes the process ID of the program to a file and
+// holds an exclusive lock on that file for the lifetime of the program, ensuring that
+// only a single instance of the program runs at a time. The file is removed and the
+// lock is released when the initializer is finalized. Register this initializer at the
+// lowest priority so that no other process starts when another instance is running.
+//
+// A program started by State.Upgrade inherits the locked PID file, and the upgraded
+// program releases it without removing the file.
+type PIDFile struct {
+	path      string
+	mu        sync.Mutex
+	file      *os.File
+	handedOff bool
+}
+
+var _ Initializer = &PIDFile{}
+var _ Finalizer = &PIDFile{}
+
+// NewPIDFile creates a PID file initializer for the file at the given path.
+func NewPIDFile(path string) *PIDFile {
+	return &PIDFile{path: path}
+}
+
+// Init acquires the lock on the PID file and writes the current process ID to it. If
+// another instance holds the lock, an error wrapping ErrPIDFileLocked is returned.
+func (p *PIDFile) Init(ctx context.Context) error {
+	file, err := p.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := writePID(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	p.mu.Lock()
+	p.file = file
+	p.handedOff = false
+	p.mu.Unlock()
+
+	return nil
+}
+
+// lock opens and locks the PID file, or adopts the locked PID file inherited from the
+// program replaced by an upgrade. Another instance may remove the file between it being
+// opened and locked here, in which case the lock on the removed file is released and the
+// file at the path is opened again.
+func (p *PIDFile) lock(ctx context.Context) (*os.File, error) {
+	if file, ok := inheritedPIDFile(p.path); ok {
+		return file, nil
+	}
+
+	for {
+		file, err := os.OpenFile(p.path, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := lockFile(file); err != nil {
+			defer file.Close()
+
+			if err == ErrPIDFileLocked {
+				if pid, ok := readPID(file); ok {
+					return nil, fmt.Errorf("%s: %w by process %d", p.path, ErrPIDFileLocked, pid)
+				}
+
+				return nil, fmt.Errorf("%s: %w", p.path, ErrPIDFileLocked)
+			}
+
+			return nil, err
+		}
+
+		same, err := isFileAtPath(file, p.path)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if same {
+			return file, nil
+		}
+
+		file.Close()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Finalize removes the PID file and releases the lock. If the PID file was handed off
+// to a program started by an upgrade, the file is left in place for that program.
+func (p *PIDFile) Finalize(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return nil
+	}
+
+	defer func() { p.file = nil }()
+
+	if p.handedOff {
+		return p.file.Close()
+	}
+
+	if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+		p.file.Close()
+		return err
+	}
+
+	return p.file.Close()
+}
+
+// lockedFile returns the locked PID file, or nil if the lock is not held.
+func (p *PIDFile) lockedFile() *os.File {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.file
+}
+
+// handOff marks the lock on the PID file as transferred to a program started by an
+// upgrade.
+func (p *PIDFile) handOff() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handedOff = true
+}
+
+// isFileAtPath returns true if the given open file is the file currently at the given path.
+func isFileAtPath(file *os.File, path string) (bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return os.SameFile(info, pathInfo), nil
+}
+
+// writePID replaces the contents of the given file with the current process ID.
+func writePID(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := file.WriteAt([]byte(fmt.Sprintf("%d\n", os.Getpid())), 0); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// readPID reads a process ID from the given file.
+func readPID(file *os.File) (int, bool) {
+	contents, err := io.ReadAll(io.NewSectionReader(file, 0, 64))
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	return pid, err == nil
+}
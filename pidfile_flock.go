@@ -0,0 +1,24 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package process
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile acquires an exclusive lock on the given file without blocking. If another
+// open file description holds the lock, ErrPIDFileLocked is returned.
+func lockFile(file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return ErrPIDFileLocked
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package process
+
+import "os"
+
+// lockFile returns ErrPIDFileUnsupported as file locking is not supported on this
+// platform.
+func lockFile(file *os.File) error {
+	return ErrPIDFileUnsupported
+}
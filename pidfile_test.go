@@ -0,0 +1,170 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIDFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pidfile")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pid")
+
+	first := NewPIDFile(path)
+	require.Nil(t, first.Init(context.Background()))
+
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("%d\n", os.Getpid()), string(contents))
+
+	second := NewPIDFile(path)
+	err = second.Init(context.Background())
+	assert.True(t, errors.Is(err, ErrPIDFileLocked))
+	assert.EqualError(t, err, fmt.Sprintf("%s: pid file locked by process %d", path, os.Getpid()))
+
+	require.Nil(t, first.Finalize(context.Background()))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	require.Nil(t, second.Init(context.Background()))
+	require.Nil(t, second.Finalize(context.Background()))
+}
+
+func TestRunPIDFileLocked(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pidfile")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pid")
+
+	other := NewPIDFile(path)
+	require.Nil(t, other.Init(context.Background()))
+	defer other.Finalize(context.Background())
+
+	builder := NewContainerBuilder()
+	builder.RegisterInitializer(NewPIDFile(path), WithMetaName("pidfile"))
+	process := NewMockMaximumProcess()
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(1))
+
+	state := Run(context.Background(), builder.Build())
+	require.False(t, state.Wait(context.Background()))
+	require.Len(t, state.Errors(), 1)
+	assert.True(t, errors.Is(state.Errors()[0], ErrPIDFileLocked))
+	assert.Empty(t, process.InitFunc.History())
+}
+
+func TestPIDFileReplacedBeforeLock(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pidfile")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pid")
+
+	first := NewPIDFile(path)
+	require.Nil(t, first.Init(context.Background()))
+
+	// Another instance opens the file just before it is removed, then locks the unlinked file
+	stale, err := os.Open(path)
+	require.Nil(t, err)
+	defer stale.Close()
+
+	require.Nil(t, first.Finalize(context.Background()))
+	require.Nil(t, lockFile(stale))
+
+	same, err := isFileAtPath(stale, path)
+	require.Nil(t, err)
+	assert.False(t, same)
+
+	// A lock on the file now at the path is not shared with the stale lock
+	second := NewPIDFile(path)
+	require.Nil(t, second.Init(context.Background()))
+	assert.True(t, errors.Is(NewPIDFile(path).Init(context.Background()), ErrPIDFileLocked))
+	require.Nil(t, second.Finalize(context.Background()))
+}
+
+const upgradeTestPIDFileEnv = "PROCESS_UPGRADE_TEST_PIDFILE"
+
+func TestUpgradePIDFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pidfile")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pid")
+
+	builder := NewContainerBuilder()
+	builder.RegisterInitializer(NewPIDFile(path), WithMetaName("pidfile"))
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(1))
+
+	os.Setenv(upgradeTestPIDFileEnv, path)
+	defer os.Unsetenv(upgradeTestPIDFileEnv)
+
+	state := Run(
+		context.Background(),
+		builder.Build(),
+		WithUpgradeExecutable(os.Args[0], "-test.run=^TestUpgradePIDFileChild$"),
+		withUpgradeOutput(io.Discard),
+	)
+	<-started
+
+	// The upgraded program adopts the lock held by this program
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	require.Nil(t, state.Upgrade(ctx))
+	require.True(t, state.Wait(context.Background()))
+
+	// Finalizing this program leaves the file owned by the upgraded program in place
+	contents, err := os.ReadFile(path)
+	require.Nil(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	require.Nil(t, err)
+	assert.NotEqual(t, os.Getpid(), pid)
+	assert.True(t, errors.Is(NewPIDFile(path).Init(context.Background()), ErrPIDFileLocked))
+
+	require.Nil(t, os.WriteFile(path+".done", nil, 0644))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return os.IsNotExist(err)
+	}, time.Second*10, time.Millisecond*10)
+}
+
+// TestUpgradePIDFileChild is the program started by TestUpgradePIDFile. It holds the
+// inherited PID file until TestUpgradePIDFile has inspected it.
+func TestUpgradePIDFileChild(t *testing.T) {
+	path := os.Getenv(upgradeTestPIDFileEnv)
+	if path == "" || os.Getenv(upgradePIDFileFDsEnv) == "" {
+		t.Skip("only run as the program started by TestUpgradePIDFile")
+	}
+
+	listeners, err := NewListenersFromEnvironment()
+	require.Nil(t, err)
+
+	builder := NewContainerBuilder()
+	builder.RegisterInitializer(NewPIDFile(path), WithMetaName("pidfile"))
+	builder.RegisterProcess(RunnerFunc(func(ctx context.Context) error {
+		for deadline := time.Now().Add(time.Second * 10); time.Now().Before(deadline); time.Sleep(time.Millisecond * 10) {
+			if _, err := os.Stat(path + ".done"); err == nil {
+				return nil
+			}
+		}
+
+		return nil
+	}), WithMetaName("child"), WithMetaPriority(1), WithEarlyExit(true))
+
+	state := Run(context.Background(), builder.Build(), WithListeners(listeners))
+	require.True(t, state.Wait(context.Background()))
+}
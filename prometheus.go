@@ -0,0 +1,170 @@
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// NewPrometheusHandler creates an http.Handler that serves the current status of the
+// components of the given health instance and the lifecycle of the processes registered
+// to the given container in the Prometheus text exposition format. Either argument may
+// be nil.
+func NewPrometheusHandler(health *Health, container *Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		if err := WritePrometheus(w, health, container); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// WritePrometheus writes the current status of the components of the given health
+// instance and the lifecycle of the processes registered to the given container to
+// the given writer in the Prometheus text exposition format. Either argument may be
+// nil. Processes sharing a name are labeled with their priority and registration index,
+// and health components whose keys format identically are labeled with an index ordered
+// by the type and Go syntax representation of the key.
+func WritePrometheus(w io.Writer, health *Health, container *Container) error {
+	bw := bufio.NewWriter(w)
+
+	if health != nil {
+		writePrometheusHealth(bw, health)
+	}
+
+	if container != nil {
+		writePrometheusLifecycle(bw, container)
+	}
+
+	return bw.Flush()
+}
+
+func writePrometheusHealth(w io.Writer, health *Health) {
+	writePrometheusHeader(w, "process_health_healthy", "gauge", "Whether all health components are healthy.")
+	fmt.Fprintf(w, "process_health_healthy %d\n", boolToInt(health.Healthy()))
+
+	type componentValue struct {
+		name     string
+		typeName string
+		value    string
+		healthy  bool
+	}
+
+	var components []componentValue
+	counts := map[string]int{}
+	for _, key := range health.Keys() {
+		if component, ok := health.Get(key); ok {
+			name := fmt.Sprintf("%v", key)
+			components = append(components, componentValue{
+				name:     name,
+				typeName: fmt.Sprintf("%T", key),
+				value:    fmt.Sprintf("%#v", key),
+				healthy:  component.Healthy(),
+			})
+			counts[name]++
+		}
+	}
+
+	// Keys are unordered; order keys that format identically by type and Go syntax
+	// representation so that each label refers to the same component on every write
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].name != components[j].name {
+			return components[i].name < components[j].name
+		}
+		if components[i].typeName != components[j].typeName {
+			return components[i].typeName < components[j].typeName
+		}
+
+		return components[i].value < components[j].value
+	})
+
+	// Distinct keys may format identically; disambiguate them so that no series is duplicated
+	indexes := map[string]int{}
+	for i, component := range components {
+		if counts[component.name] > 1 {
+			components[i].name = fmt.Sprintf("%s (index %d)", component.name, indexes[component.name])
+			indexes[component.name]++
+		}
+	}
+
+	writePrometheusHeader(w, "process_health_component_healthy", "gauge", "Whether the health component is healthy.")
+	for _, component := range components {
+		fmt.Fprintf(w, "process_health_component_healthy{key=%s} %d\n", quotePrometheusLabel(component.name), boolToInt(component.healthy))
+	}
+}
+
+func writePrometheusLifecycle(w io.Writer, container *Container) {
+	counts := map[string]int{}
+	for _, meta := range container.Meta() {
+		counts[meta.Name()]++
+	}
+
+	// Processes may share a name (e.g., unnamed processes of the same type); disambiguate
+	// them by priority and registration index so that no series is duplicated
+	var names []string
+	lifecycles := map[string]Lifecycle{}
+	for _, priority := range container.Priorities() {
+		for i, meta := range container.MetaForPriority(priority) {
+			name := meta.Name()
+			if counts[name] > 1 {
+				name = fmt.Sprintf("%s (priority %d, index %d)", name, priority, i)
+			}
+
+			names = append(names, name)
+			lifecycles[name] = meta.Lifecycle()
+		}
+	}
+
+	writePrometheusHeader(w, "process_phase", "gauge", "Whether the lifecycle phase is the most recently entered phase of the process.")
+	for _, name := range names {
+		for _, phase := range Phases {
+			fmt.Fprintf(w, "process_phase{process=%s,phase=%s} %d\n", quotePrometheusLabel(name), quotePrometheusLabel(phase), boolToInt(lifecycles[name].Phase == phase))
+		}
+	}
+
+	writePrometheusHeader(w, "process_restarts_total", "counter", "The number of times the process has been restarted.")
+	for _, name := range names {
+		fmt.Fprintf(w, "process_restarts_total{process=%s} %d\n", quotePrometheusLabel(name), lifecycles[name].Restarts)
+	}
+
+	writePrometheusHeader(w, "process_phase_duration_seconds", "gauge", "The duration of the completed lifecycle phase of the process.")
+	for _, name := range names {
+		for _, phase := range Phases {
+			if duration, ok := lifecycles[name].PhaseDurations[phase]; ok {
+				fmt.Fprintf(w, "process_phase_duration_seconds{process=%s,phase=%s} %g\n", quotePrometheusLabel(name), quotePrometheusLabel(phase), duration.Seconds())
+			}
+		}
+	}
+
+	writePrometheusHeader(w, "process_last_error", "gauge", "The kind of the most recent error returned by the process.")
+	for _, name := range names {
+		if kind := lifecycles[name].LastErrorKind; kind != "" {
+			fmt.Fprintf(w, "process_last_error{process=%s,kind=%s} 1\n", quotePrometheusLabel(name), quotePrometheusLabel(kind))
+		}
+	}
+}
+
+func writePrometheusHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// prometheusLabelReplacer escapes label values per the text exposition format.
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quotePrometheusLabel returns the given value as a quoted label value.
+func quotePrometheusLabel(value string) string {
+	return `"` + prometheusLabelReplacer.Replace(value) + `"`
+}
+
+func boolToInt(v bool) int {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	health := NewHealth()
+	a, _ := health.Register("a")
+	_, _ = health.Register(`b"c`)
+	a.Update(true)
+
+	clock := glock.NewMockClock()
+	wrapped := NewMockMaximumProcess()
+	wrapped.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		clock.Advance(time.Millisecond * 1500)
+		return nil
+	})
+	wrapped.FinalizeFunc.SetDefaultReturn(testErr1)
+
+	builder := NewContainerBuilder()
+	builder.RegisterInitializer(wrapped, WithMetaName("test-service"), withMetaLifecycleClock(clock))
+	container := builder.Build()
+	meta := container.Meta()[0]
+	require.Nil(t, meta.Init(context.Background()))
+	require.NotNil(t, meta.Finalize(context.Background()))
+
+	recorder := httptest.NewRecorder()
+	NewPrometheusHandler(health, container).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP process_health_healthy Whether all health components are healthy.
+# TYPE process_health_healthy gauge
+process_health_healthy 0
+# HELP process_health_component_healthy Whether the health component is healthy.
+# TYPE process_health_component_healthy gauge
+process_health_component_healthy{key="a"} 1
+process_health_component_healthy{key="b\"c"} 0
+# HELP process_phase Whether the lifecycle phase is the most recently entered phase of the process.
+# TYPE process_phase gauge
+process_phase{process="test-service",phase="inject"} 0
+process_phase{process="test-service",phase="init"} 0
+process_phase{process="test-service",phase="run"} 0
+process_phase{process="test-service",phase="stop"} 0
+process_phase{process="test-service",phase="finalize"} 1
+# HELP process_restarts_total The number of times the process has been restarted.
+# TYPE process_restarts_total counter
+process_restarts_total{process="test-service"} 0
+# HELP process_phase_duration_seconds The duration of the completed lifecycle phase of the process.
+# TYPE process_phase_duration_seconds gauge
+process_phase_duration_seconds{process="test-service",phase="init"} 1.5
+process_phase_duration_seconds{process="test-service",phase="finalize"} 0
+# HELP process_last_error The kind of the most recent error returned by the process.
+# TYPE process_last_error gauge
+process_last_error{process="test-service",kind="failed"} 1
+`, recorder.Body.String())
+}
+
+func TestPrometheusDuplicateNames(t *testing.T) {
+	builder := NewContainerBuilder()
+	builder.RegisterInitializer(NewMockMaximumProcess())
+	builder.RegisterInitializer(NewMockMaximumProcess())
+	builder.RegisterInitializer(NewMockMaximumProcess(), WithMetaPriority(1))
+	builder.RegisterInitializer(NewMockMaximumProcess(), WithMetaName("named"))
+
+	var buf bytes.Buffer
+	require.Nil(t, WritePrometheus(&buf, nil, builder.Build()))
+	assert.Contains(t, buf.String(), `# TYPE process_restarts_total counter
+process_restarts_total{process="<unnamed *process.MockMaximumProcess> (priority 0, index 0)"} 0
+process_restarts_total{process="<unnamed *process.MockMaximumProcess> (priority 0, index 1)"} 0
+process_restarts_total{process="named"} 0
+process_restarts_total{process="<unnamed *process.MockMaximumProcess> (priority 1, index 0)"} 0
+`)
+}
+
+type prometheusTestKey string
+
+func (k prometheusTestKey) String() string { return string(k) }
+
+func TestPrometheusDuplicateHealthKeys(t *testing.T) {
+	health := NewHealth()
+	a, _ := health.Register(prometheusTestKey("a"))
+	_, _ = health.Register("a")
+	a.Update(true)
+
+	var expected string
+	for i := 0; i < 50; i++ {
+		var buf bytes.Buffer
+		require.Nil(t, WritePrometheus(&buf, health, nil))
+
+		if i == 0 {
+			expected = buf.String()
+		}
+		require.Equal(t, expected, buf.String())
+	}
+
+	assert.Contains(t, expected, `process_health_component_healthy{key="a (index 0)"} 1
+process_health_component_healthy{key="a (index 1)"} 0
+`)
+}
@@ -0,0 +1,59 @@
+package process
+
+import "context"
+
+// PhaseReload is the name of the hook invoked when a process is asked to reload. It is
+// passed to interceptors but, unlike the lifecycle phases, is not recorded as the phase
+// of the process.
+const PhaseReload = "reload"
+
+// Reload invokes the wrapped value's Reload method.
+//
+// This method will no-op if the meta instance was not initialized or is stopping.
+func (m *Meta) Reload(ctx context.Context) error {
+	reloader, ok := m.wrapped.(Reloader)
+	if !ok || !m.shouldReload() {
+		return nil
+	}
+
+	m.logger.Info("%s: reloading", m.Name())
+
+	ctx = m.hookContext(ctx)
+	if err := m.intercept(PhaseReload, reloader.Reload)(ctx); err != nil {
+		return &opError{
+			source:   err,
+			metaName: m.Name(),
+			opName:   PhaseReload,
+			message:  "failed",
+		}
+	}
+
+	return nil
+}
+
+func (m *Meta) shouldReload() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.initialized && !m.stopping
+}
+
+// Reload invokes the Reload method of each process. Processes registered to the same
+// priority are reloaded in parallel and processes with a lower priority are reloaded
+// before those registered to a higher priority. A MultiError is returned if more than
+// one process fails to reload.
+func (s *State) Reload(ctx context.Context) error {
+	var reloadEachPriority []streamErrorFunc
+	for _, priority := range s.container.priorities {
+		reloadEachPriority = append(reloadEachPriority, mapMetaParallel(s.container.meta[priority], func(m *Meta) streamErrorFunc {
+			return toStreamErrorFunc(m.Reload)
+		}))
+	}
+
+	var errs []error
+	for err := range sequence(reloadEachPriority...)(ctx) {
+		errs = append(errs, err)
+	}
+
+	return errorOrNil(errs)
+}
@@ -2,6 +2,7 @@ package process
 
 import (
 	"context"
+	"errors"
 	"sync"
 )
 
@@ -10,10 +11,19 @@ type State struct {
 	stateLock sync.RWMutex
 
 	machine      *machine
+	container    *Container
+	health       *Health
+	listeners    *Listeners
+	upgrader     *upgrader
 	shutdownOnce sync.Once
+	timeline     *timeline
+	startup      *startupRecorder
+	observer     *observerNotifier
 
 	errors     <-chan error
+	done       chan struct{}
 	errorsSeen []error
+	restarts   []error
 }
 
 // Run builds a machine to invoke the processes registered to the given container. This
@@ -26,31 +36,72 @@ func Run(ctx context.Context, container *Container, configs ...MachineConfigFunc
 
 	errors := make(chan error)
 	machine := newMachine(runFunc, shutdownFunc, errors)
-	machine.run(ctx)
 
-	return &State{machine: machine, errors: errors}
+	state := &State{
+		machine:   machine,
+		container: container,
+		health:    machineBuilder.health,
+		listeners: machineBuilder.listeners,
+		upgrader:  machineBuilder.upgrader,
+		errors:    errors,
+		done:      make(chan struct{}),
+		timeline:  machineBuilder.timeline,
+		startup:   machineBuilder.startup,
+		observer:  machineBuilder.observer,
+	}
+
+	machine.run(ctx)
+	go state.watchErrors()
+	return state
 }
 
-// Wait blocks until all processes exit cleanly or until an error occurs during execution
-// of the machine built via Run. If an error occurs, all other running processes are
-// signalled to exit. This method unblocks once all processes have exited. This method
-// returns a boolean flag indicating a clean exit.
-func (s *State) Wait(ctx context.Context) bool {
-	ok := true
+// watchErrors records the errors emitted by the machine until all processes have exited.
+// If an error occurs, all other running processes are signalled to exit. Once the machine
+// completes, the observer is notified that shutdown is complete, whether or not the Wait
+// method is called.
+func (s *State) watchErrors() {
+	defer close(s.done)
+
 	for err := range s.errors {
-		ok = false
-		s.Shutdown(ctx)
+		if isRestartError(err) {
+			s.stateLock.Lock()
+			s.restarts = append(s.restarts, err)
+			s.stateLock.Unlock()
+			continue
+		}
+
+		s.shutdown(context.Background(), err)
 
 		s.stateLock.Lock()
 		s.errorsSeen = append(s.errorsSeen, err)
 		s.stateLock.Unlock()
 	}
 
-	return ok
+	s.observer.shutdownComplete(s.Errors())
+}
+
+// Wait blocks until all processes exit cleanly or until an error occurs during execution
+// of the machine built via Run. This method unblocks once all processes have exited. This
+// method returns a boolean flag indicating a clean exit.
+//
+// If an error occurs, all other running processes are signalled to exit with a background
+// context as soon as the error occurs, whether or not this method has been called. The
+// given context is unused.
+func (s *State) Wait(ctx context.Context) bool {
+	<-s.done
+	return len(s.Errors()) == 0
+}
+
+// isRestartError returns true if the given error was reported by a process that was
+// subsequently restarted. Such errors do not signal the application to shut down.
+func isRestartError(err error) bool {
+	var watchdogErr *WatchdogError
+	return errors.As(err, &watchdogErr) && watchdogErr.Restarted
 }
 
 // Errors returns a slice of errors encountered while running the processes. The Errors method can
-// be used once the Wait method returns to find the errors returned by the processes.
+// be used once the Wait method returns to find the errors returned by the processes. Errors
+// reported by processes that were subsequently restarted are not included (see Restarts).
 func (s *State) Errors() []error {
 	s.stateLock.RLock()
 	defer s.stateLock.RUnlock()
@@ -58,9 +109,35 @@ func (s *State) Errors() []error {
 	return s.errorsSeen
 }
 
+// Restarts returns a slice of errors reported by processes that were subsequently restarted
+// (see WithMetaWatchdogRestart). These errors do not prevent a clean exit.
+func (s *State) Restarts() []error {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+
+	return s.restarts
+}
+
 // Shutdown signals all running processes to exit.
 func (s *State) Shutdown(ctx context.Context) {
+	s.shutdown(ctx, nil)
+}
+
+// shutdown signals all running processes to exit due to the given reason.
+func (s *State) shutdown(ctx context.Context, reason error) {
 	s.shutdownOnce.Do(func() {
+		s.observer.shutdownBegin(reason)
 		s.machine.shutdown(ctx)
 	})
 }
+
+// Get returns the meta value with the given name registered to the container being run.
+func (s *State) Get(name string) (*Meta, bool) {
+	return s.container.Get(name)
+}
+
+// Names returns the names of the meta values registered to the container being run
+// ordered by priority, then by registration order.
+func (s *State) Names() []string {
+	return s.container.Names()
+}
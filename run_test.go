@@ -1,10 +1,16 @@
 package process
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/derision-test/glock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -722,3 +728,452 @@ func TestRunErrorsDuringShutdown(t *testing.T) {
 		),
 	})
 }
+
+type testMetrics struct {
+	mu          sync.Mutex
+	phases      []string
+	transitions []string
+}
+
+func (m *testMetrics) PhaseDuration(name, phase string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phases = append(m.phases, fmt.Sprintf("%s.%s.%v", name, phase, err))
+}
+
+func (m *testMetrics) Restart(name string) {}
+
+func (m *testMetrics) HealthTransition(key interface{}, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transitions = append(m.transitions, fmt.Sprintf("%s.%v", key, healthy))
+}
+
+func (m *testMetrics) snapshot() ([]string, []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.phases...), append([]string(nil), m.transitions...)
+}
+
+func TestRunMetrics(t *testing.T) {
+	health := NewHealth()
+	metrics := &testMetrics{}
+	trace := make(chan string, 72)
+	builder := NewContainerBuilder()
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(traceInit(health, trace, "a", 0, nil))
+	process.RunFunc.SetDefaultHook(traceRun(health, trace, "a", 0, nil))
+	process.StopFunc.SetDefaultHook(traceStop(trace, "a", 0, nil))
+	process.FinalizeFunc.SetDefaultHook(traceFinalize(trace, "a", 0, testErr1))
+	builder.RegisterProcess(process, WithMetaName("a"), WithMetaHealthKey(testHealthKey("a", 0)))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithMetrics(metrics))
+	assertChannelContents(t, readStringChannel(forwardN(trace, 2)), seq("a.0.init", "a.0.run"))
+	require.Eventually(t, func() bool {
+		_, transitions := metrics.snapshot()
+		return len(transitions) == 1
+	}, time.Second, time.Millisecond)
+
+	state.Shutdown(context.Background())
+	require.False(t, state.Wait(context.Background()))
+
+	phases, transitions := metrics.snapshot()
+	assert.Equal(t, []string{"a.0.true"}, transitions)
+	assert.Equal(t, []string{
+		"a.inject.<nil>",
+		"a.init.<nil>",
+		"a.stop.<nil>",
+		"a.run.<nil>",
+		"a.finalize.a: finalize failed (oops1)",
+	}, phases)
+}
+
+// lifecycleMetrics inspects the lifecycle of a process from within each metrics callback.
+type lifecycleMetrics struct {
+	Metrics
+	meta   *Meta
+	phases chan string
+}
+
+func (m *lifecycleMetrics) PhaseDuration(name, phase string, duration time.Duration, err error) {
+	m.phases <- m.meta.Lifecycle().Phase
+}
+
+func TestRunMetricsInspectLifecycle(t *testing.T) {
+	metrics := &lifecycleMetrics{Metrics: NilMetrics, phases: make(chan string, 5)}
+	process := NewMockMaximumProcess()
+	process.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(process, WithMetaName("a"))
+	container := builder.Build()
+	metrics.meta, _ = container.Get("a")
+
+	state := Run(context.Background(), container, WithMetrics(metrics))
+	require.Eventually(t, func() bool {
+		_, ok := state.StartupReport()
+		return ok
+	}, time.Second*5, time.Millisecond)
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+	assert.Len(t, metrics.phases, 5)
+}
+
+type testSpanKey struct{}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+type testSpan struct {
+	tracer *testTracer
+	name   string
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span) {
+	return context.WithValue(ctx, testSpanKey{}, name), &testSpan{tracer: t, name: fmt.Sprintf("%s.%s.%s", attributes["process.name"], name, attributes["team"])}
+}
+
+func (t *testTracer) snapshot() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.spans...)
+}
+
+func (s *testSpan) End(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, fmt.Sprintf("%s.%v", s.name, err))
+}
+
+func TestRunTracer(t *testing.T) {
+	health := NewHealth()
+	tracer := &testTracer{}
+	trace := make(chan string, 72)
+	builder := NewContainerBuilder()
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		trace <- fmt.Sprintf("init in span %s", ctx.Value(testSpanKey{}))
+		return traceInit(health, trace, "a", 0, nil)(ctx)
+	})
+	process.RunFunc.SetDefaultHook(traceRun(health, trace, "a", 0, nil))
+	process.StopFunc.SetDefaultHook(traceStop(trace, "a", 0, nil))
+	builder.RegisterProcess(process, WithMetaName("a"), WithMetadata(map[string]interface{}{"team": "x"}), WithMetaHealthKey(testHealthKey("a", 0)))
+
+	injecter := InjecterFunc(func(ctx context.Context, meta *Meta) error {
+		trace <- fmt.Sprintf("inject in span %s", ctx.Value(testSpanKey{}))
+		return nil
+	})
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithInjecter(injecter), WithTracer(tracer))
+	assertChannelContents(t, readStringChannel(forwardN(trace, 4)), seq("inject in span inject", "init in span init", "a.0.init", "a.0.run"))
+	require.Eventually(t, func() bool { return len(tracer.snapshot()) == 3 }, time.Second, time.Millisecond)
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+
+	assert.Equal(t, []string{
+		"a.inject.x.<nil>",
+		"a.init.x.<nil>",
+		"a.run.x.<nil>",
+		"a.stop.x.<nil>",
+		"a.finalize.x.<nil>",
+	}, tracer.snapshot())
+}
+
+func TestRunWriteTimeline(t *testing.T) {
+	health := NewHealth()
+	trace := make(chan string, 72)
+	builder := NewContainerBuilder()
+
+	initializer := NewMockMaximumProcess()
+	builder.RegisterInitializer(initializer, WithMetaName("i"), WithMetaPriority(1))
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(traceInit(health, trace, "p", 0, nil))
+	process.RunFunc.SetDefaultHook(traceRun(health, trace, "p", 0, nil))
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(2), WithMetaHealthKey(testHealthKey("p", 0)))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health))
+	assertChannelContents(t, readStringChannel(forwardN(trace, 2)), seq("p.0.init", "p.0.run"))
+	require.Eventually(t, func() bool {
+		var buf bytes.Buffer
+		require.Nil(t, state.WriteTimeline(&buf))
+		return strings.Contains(buf.String(), "wait until healthy")
+	}, time.Second, time.Millisecond)
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+
+	var buf bytes.Buffer
+	require.Nil(t, state.WriteTimeline(&buf))
+
+	var payload struct {
+		TraceEvents []struct {
+			Name  string                 `json:"name"`
+			Phase string                 `json:"ph"`
+			PID   int                    `json:"pid"`
+			TID   int                    `json:"tid"`
+			Args  map[string]interface{} `json:"args"`
+		} `json:"traceEvents"`
+	}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &payload))
+
+	lanes := map[string]string{}
+	events := map[string][]string{}
+	for _, event := range payload.TraceEvents {
+		lane := fmt.Sprintf("%d.%d", event.PID, event.TID)
+		switch event.Phase {
+		case "M":
+			if event.Name == "thread_name" {
+				lanes[lane] = event.Args["name"].(string)
+			}
+		case "X":
+			events[lanes[lane]] = append(events[lanes[lane]], event.Name)
+		}
+	}
+
+	assert.Equal(t, map[string]string{"1.0": "priority 1", "1.1": "i", "2.0": "priority 2", "2.1": "p"}, lanes)
+	assert.Equal(t, map[string][]string{
+		"i":          {"inject", "init", "run", "finalize"},
+		"p":          {"inject", "init", "run until healthy", "run", "stop", "finalize"},
+		"priority 2": {"wait until healthy"},
+	}, events)
+}
+
+type testLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *testLogger) WithFields(LogFields) Logger             { return l }
+func (l *testLogger) Debug(msg string, args ...interface{})   { l.log("DEBUG", msg, args...) }
+func (l *testLogger) Info(msg string, args ...interface{})    { l.log("INFO", msg, args...) }
+func (l *testLogger) Warning(msg string, args ...interface{}) { l.log("WARN", msg, args...) }
+func (l *testLogger) Error(msg string, args ...interface{})   { l.log("ERROR", msg, args...) }
+
+func (l *testLogger) log(level, msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, level+": "+fmt.Sprintf(msg, args...))
+}
+
+func (l *testLogger) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.messages...)
+}
+
+func TestRunStartupReport(t *testing.T) {
+	health := NewHealth()
+	logger := &testLogger{}
+	trace := make(chan string, 72)
+	clock := glock.NewMockClock()
+	builder := NewContainerBuilder()
+
+	fast := NewMockMaximumProcess()
+	builder.RegisterInitializer(fast, WithMetaName("fast"), WithMetaPriority(1), withMetaLifecycleClock(glock.NewMockClock()))
+
+	slow := NewMockMaximumProcess()
+	slow.InitFunc.SetDefaultHook(func(ctx context.Context) error {
+		clock.Advance(time.Millisecond * 20)
+		return nil
+	})
+	builder.RegisterInitializer(slow, WithMetaName("slow"), WithMetaPriority(1), withMetaLifecycleClock(clock))
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(traceInit(health, trace, "p", 0, nil))
+	process.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(2), WithMetaHealthKey(testHealthKey("p", 0)), withMetaLifecycleClock(clock))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithLogger(logger), withStartupClock(clock))
+	_, ok := state.StartupReport()
+	assert.False(t, ok)
+
+	// Become healthy only once both the process and the machine are waiting on its health
+	require.Eventually(t, func() bool {
+		health.mu.Lock()
+		defer health.mu.Unlock()
+
+		n := 0
+		for _, subscriber := range health.changeSubscribers {
+			if subscriber != nil {
+				if _, ok := subscriber.keys[testHealthKey("p", 0)]; ok {
+					n++
+				}
+			}
+		}
+
+		return n == 2
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Millisecond * 20)
+	component, ok := health.Get(testHealthKey("p", 0))
+	require.True(t, ok)
+	component.Update(true)
+
+	var report StartupReport
+	require.Eventually(t, func() bool {
+		report, ok = state.StartupReport()
+		return ok
+	}, time.Second, time.Millisecond)
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+
+	require.Len(t, report.Priorities, 2)
+	assert.Equal(t, 1, report.Priorities[0].Priority)
+	assert.Equal(t, "slow", report.Priorities[0].Slowest)
+	assert.Equal(t, time.Millisecond*20, report.Priorities[0].Init)
+	assert.Equal(t, time.Millisecond*20, report.Priorities[0].SlowestDuration)
+	assert.Equal(t, 2, report.Priorities[1].Priority)
+	assert.Equal(t, "p", report.Priorities[1].Slowest)
+	assert.Equal(t, time.Millisecond*20, report.Priorities[1].HealthWait)
+	assert.Equal(t, time.Millisecond*20, report.Priorities[1].SlowestDuration)
+	assert.Equal(t, time.Millisecond*40, report.Total)
+
+	messages := logger.snapshot()
+	require.Len(t, messages, 3)
+	assert.Equal(t, "INFO: Startup completed in 40ms", messages[0])
+	assert.Contains(t, messages[1], "INFO: Priority 1 started in 20ms")
+	assert.Contains(t, messages[1], "slowest process slow took 20ms")
+	assert.Contains(t, messages[2], "slowest process p took 20ms")
+}
+
+func TestRunInterceptor(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+
+	interceptor := func(name string) Interceptor {
+		return func(ctx context.Context, info HookInfo, next func(ctx context.Context) error) error {
+			mu.Lock()
+			calls = append(calls, fmt.Sprintf("%s.%s.%s", name, info.Name, info.Phase))
+			mu.Unlock()
+			return next(ctx)
+		}
+	}
+
+	health := NewHealth()
+	trace := make(chan string, 72)
+	builder := NewContainerBuilder()
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(traceInit(health, trace, "p", 0, nil))
+	process.RunFunc.SetDefaultHook(traceRun(health, trace, "p", 0, nil))
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(1), WithMetaHealthKey(testHealthKey("p", 0)), WithMetaInterceptor(interceptor("meta")))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithInterceptor(interceptor("machine")))
+	assertChannelContents(t, readStringChannel(forwardN(trace, 2)), seq("p.0.init", "p.0.run"))
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"machine.p.inject", "meta.p.inject",
+		"machine.p.init", "meta.p.init",
+		"machine.p.run", "meta.p.run",
+		"machine.p.stop", "meta.p.stop",
+		"machine.p.finalize", "meta.p.finalize",
+	}, calls)
+}
+
+type testObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *testObserver) OnPriorityStarted(priority int) { o.record("started.%d", priority) }
+func (o *testObserver) OnPriorityHealthy(priority int) { o.record("healthy.%d", priority) }
+func (o *testObserver) OnApplicationReady()            { o.record("ready") }
+func (o *testObserver) OnShutdownBegin(reason error)   { o.record("shutdown.begin.%v", reason) }
+func (o *testObserver) OnShutdownComplete(errors []error) {
+	o.record("shutdown.complete.%d", len(errors))
+}
+
+func (o *testObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *testObserver) snapshot() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.events...)
+}
+
+func TestRunObserver(t *testing.T) {
+	health := NewHealth()
+	observer := &testObserver{}
+	trace := make(chan string, 72)
+	builder := NewContainerBuilder()
+
+	initializer := NewMockMaximumProcess()
+	builder.RegisterInitializer(initializer, WithMetaName("i"), WithMetaPriority(1))
+
+	process := NewMockMaximumProcess()
+	process.InitFunc.SetDefaultHook(traceInit(health, trace, "p", 0, nil))
+	process.RunFunc.SetDefaultHook(traceRun(health, trace, "p", 0, nil))
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(2), WithMetaHealthKey(testHealthKey("p", 0)))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithObserver(observer))
+	require.Eventually(t, func() bool {
+		events := observer.snapshot()
+		return len(events) > 0 && events[len(events)-1] == "ready"
+	}, time.Second, time.Millisecond)
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+
+	assert.Equal(t, []string{
+		"started.1", "healthy.1",
+		"started.2", "healthy.2",
+		"ready",
+		"shutdown.begin.<nil>",
+		"shutdown.complete.0",
+	}, observer.snapshot())
+}
+
+func TestRunObserverWithoutWait(t *testing.T) {
+	observer := &testObserver{}
+	builder := NewContainerBuilder()
+
+	process := NewMockMaximumProcess()
+	process.RunFunc.SetDefaultReturn(testErr1)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	// Errors shut the application down and shutdown completes without a call to Wait
+	Run(context.Background(), builder.Build(), WithObserver(observer))
+	require.Eventually(t, func() bool {
+		events := observer.snapshot()
+		return len(events) > 0 && events[len(events)-1] == "shutdown.complete.1"
+	}, time.Second, time.Millisecond)
+}
+
+func TestRunObserverProcessError(t *testing.T) {
+	observer := &testObserver{}
+	builder := NewContainerBuilder()
+
+	process := NewMockMaximumProcess()
+	process.RunFunc.SetDefaultReturn(testErr1)
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(1))
+
+	state := Run(context.Background(), builder.Build(), WithObserver(observer))
+	require.False(t, state.Wait(context.Background()))
+
+	assert.Equal(t, []string{
+		"started.1", "healthy.1",
+		"ready",
+		"shutdown.begin.p: run failed (oops1)",
+		"shutdown.complete.1",
+	}, observer.snapshot())
+}
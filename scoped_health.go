@@ -0,0 +1,72 @@
+package process
+
+import (
+	"sync"
+	"time"
+)
+
+// ScopedHealth registers components to a health instance on behalf of a single
+// process. Components registered through a scoped health value are unregistered
+// once the owning process is stopped or finalized.
+type ScopedHealth struct {
+	health *Health
+	mu     sync.Mutex
+	keys   []interface{}
+}
+
+func newScopedHealth(health *Health) *ScopedHealth {
+	return &ScopedHealth{
+		health: health,
+	}
+}
+
+// Health returns the underlying health instance.
+func (s *ScopedHealth) Health() *Health {
+	return s.health
+}
+
+// Register creates and returns a new component status value for the given key.
+// See Health.Register.
+func (s *ScopedHealth) Register(key interface{}) (*HealthComponentStatus, error) {
+	return s.track(key)(s.health.Register(key))
+}
+
+// RegisterWithTTL creates and returns a new component status value for the given
+// key that must receive heartbeats within the given TTL. See Health.RegisterWithTTL.
+func (s *ScopedHealth) RegisterWithTTL(key interface{}, ttl time.Duration) (*HealthComponentStatus, error) {
+	return s.track(key)(s.health.RegisterWithTTL(key, ttl))
+}
+
+// RegisterDerived creates and returns a new component status value for the given key
+// computed from the given dependencies. See Health.RegisterDerived.
+func (s *ScopedHealth) RegisterDerived(key interface{}, rule HealthRule, deps ...interface{}) (*HealthComponentStatus, error) {
+	return s.track(key)(s.health.RegisterDerived(key, rule, deps...))
+}
+
+// track returns a function that records the given key on a successful registration.
+func (s *ScopedHealth) track(key interface{}) func(*HealthComponentStatus, error) (*HealthComponentStatus, error) {
+	return func(component *HealthComponentStatus, err error) (*HealthComponentStatus, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.keys = append(s.keys, key)
+		s.mu.Unlock()
+
+		return component, nil
+	}
+}
+
+// unregisterAll removes all components registered through this value from the
+// underlying health instance.
+func (s *ScopedHealth) unregisterAll() {
+	s.mu.Lock()
+	keys := s.keys
+	s.keys = nil
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		_ = s.health.Unregister(key)
+	}
+}
@@ -0,0 +1,209 @@
+package process
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+// StartupReport describes where time was spent while starting the processes
+// registered to a container.
+type StartupReport struct {
+	// Total is the time between the start of the application and the moment the
+	// processes registered to the last priority became healthy.
+	Total time.Duration
+
+	// Priorities holds a breakdown of the startup time of each priority, ordered
+	// from low values to high values.
+	Priorities []PriorityStartupReport
+}
+
+// PriorityStartupReport describes where time was spent while starting the processes
+// registered to a single priority.
+type PriorityStartupReport struct {
+	// Priority is the priority described by this report.
+	Priority int
+
+	// Inject, Init, Run, and HealthWait are the times spent running the inject hooks,
+	// initializing, starting, and waiting for the processes of this priority to become
+	// healthy, respectively.
+	Inject     time.Duration
+	Init       time.Duration
+	Run        time.Duration
+	HealthWait time.Duration
+
+	// Slowest is the name of the process that was last to become ready, and therefore
+	// gated the start of the next priority. SlowestDuration is the sum of the time that
+	// process spent in its inject hook, initializing, and waiting to become healthy.
+	Slowest         string
+	SlowestDuration time.Duration
+}
+
+// Duration returns the total time spent starting the processes of this priority.
+func (r PriorityStartupReport) Duration() time.Duration {
+	return r.Inject + r.Init + r.Run + r.HealthWait
+}
+
+// startupRecorder collects the timings of each startup step of the machine so that
+// a report can be produced once all priorities have become healthy.
+type startupRecorder struct {
+	mu         sync.Mutex
+	clock      glock.Clock
+	start      time.Time
+	priorities []*priorityStartupRecorder
+	report     *StartupReport
+}
+
+type priorityStartupRecorder struct {
+	PriorityStartupReport
+	meta       []*Meta
+	healthWait map[*Meta]time.Duration
+}
+
+func newStartupRecorder() *startupRecorder {
+	return &startupRecorder{clock: defaultClock}
+}
+
+// addPriority begins tracking the startup steps of the given processes registered to the
+// given priority.
+func (r *startupRecorder) addPriority(priority int, meta []*Meta) *priorityStartupRecorder {
+	p := &priorityStartupRecorder{
+		PriorityStartupReport: PriorityStartupReport{Priority: priority},
+		meta:                  meta,
+		healthWait:            map[*Meta]time.Duration{},
+	}
+
+	r.priorities = append(r.priorities, p)
+	return p
+}
+
+// begin creates a function that marks the start of the application.
+func (r *startupRecorder) begin() streamErrorFunc {
+	return toStreamErrorFunc(func(ctx context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.start = r.clock.Now()
+		return nil
+	})
+}
+
+// timed creates a function that invokes the given function and adds the time it took
+// for its error stream to close to the given duration.
+func (r *startupRecorder) timed(fn streamErrorFunc, total *time.Duration) streamErrorFunc {
+	return func(ctx context.Context) <-chan error {
+		return withErrors(func(errs chan<- error) {
+			start := r.clock.Now()
+
+			for err := range fn(ctx) {
+				errs <- err
+			}
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			*total += r.clock.Since(start)
+		})
+	}
+}
+
+// recordHealthWait records the time the given process waited to become healthy after the
+// processes of its priority were started.
+func (r *startupRecorder) recordHealthWait(p *priorityStartupRecorder, meta *Meta, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.healthWait[meta] = d
+}
+
+// finish creates a function that builds the startup report from the collected timings
+// and writes it to the given logger.
+func (r *startupRecorder) finish(logger Logger) streamErrorFunc {
+	return toStreamErrorFunc(func(ctx context.Context) error {
+		report := r.build()
+		logStartupReport(logger, report)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.report = &report
+		return nil
+	})
+}
+
+// build creates a startup report from the collected timings.
+func (r *startupRecorder) build() StartupReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := StartupReport{
+		Total:      r.clock.Since(r.start),
+		Priorities: make([]PriorityStartupReport, 0, len(r.priorities)),
+	}
+
+	for _, p := range r.priorities {
+		priorityReport := p.PriorityStartupReport
+
+		for _, meta := range p.meta {
+			durations := meta.Lifecycle().PhaseDurations
+			duration := durations[PhaseInject] + durations[PhaseInit] + p.healthWait[meta]
+
+			if priorityReport.Slowest == "" || duration > priorityReport.SlowestDuration {
+				priorityReport.Slowest = meta.Name()
+				priorityReport.SlowestDuration = duration
+			}
+		}
+
+		report.Priorities = append(report.Priorities, priorityReport)
+	}
+
+	return report
+}
+
+// get returns the startup report, or false if startup has not yet completed.
+func (r *startupRecorder) get() (StartupReport, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.report == nil {
+		return StartupReport{}, false
+	}
+
+	return *r.report, true
+}
+
+// logStartupReport writes the given startup report to the given logger.
+func logStartupReport(logger Logger, report StartupReport) {
+	logger.WithFields(LogFields{
+		"duration": report.Total,
+	}).Info("Startup completed in %s", report.Total)
+
+	for _, p := range report.Priorities {
+		logger.WithFields(LogFields{
+			"priority":         p.Priority,
+			"inject":           p.Inject,
+			"init":             p.Init,
+			"run":              p.Run,
+			"health_wait":      p.HealthWait,
+			"slowest":          p.Slowest,
+			"slowest_duration": p.SlowestDuration,
+		}).Info(
+			"Priority %d started in %s (inject %s, init %s, run %s, health wait %s); slowest process %s took %s",
+			p.Priority,
+			p.Duration(),
+			p.Inject,
+			p.Init,
+			p.Run,
+			p.HealthWait,
+			p.Slowest,
+			p.SlowestDuration,
+		)
+	}
+}
+
+// StartupReport returns a breakdown of the time spent starting the application, or false
+// if the processes registered to every priority have not yet become healthy.
+func (s *State) StartupReport() (StartupReport, bool) {
+	return s.startup.get()
+}
@@ -0,0 +1,42 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteStatus writes the current lifecycle phase of each process and the status of each
+// health component to the given writer in a human-readable format.
+func (s *State) WriteStatus(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "processes:\n"); err != nil {
+		return err
+	}
+
+	for _, meta := range s.container.Meta() {
+		lifecycle := meta.Lifecycle()
+		if _, err := fmt.Fprintf(w, "  %s: phase=%s restarts=%d last_error=%s\n", meta.Name(), lifecycle.Phase, lifecycle.Restarts, lifecycle.LastErrorKind); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "health: healthy=%v\n", s.health.Healthy()); err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(s.health.Keys()))
+	for _, key := range s.health.Keys() {
+		if component, ok := s.health.Get(key); ok {
+			lines = append(lines, fmt.Sprintf("  %v: healthy=%v\n", key, component.Healthy()))
+		}
+	}
+	sort.Strings(lines)
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+// SystemdNotifier is an Observer that reports the readiness and status of the
+// application to systemd over the socket named by $NOTIFY_SOCKET. If the variable
+// is not set, the notifier does nothing.
+//
+// READY=1 is sent once the processes registered to every priority are healthy and
+// STOPPING=1 is sent once shutdown begins. STATUS= messages describe the current
+// phase of the process runner. If the service manager enabled the watchdog (via
+// $WATCHDOG_USEC), WATCHDOG=1 is sent at half the watchdog interval while the given
+// health instance is healthy.
+type SystemdNotifier struct {
+	health           *Health
+	socket           string
+	watchdogInterval time.Duration
+	logger           Logger
+	clock            glock.Clock
+	stopOnce         sync.Once
+	stop             chan struct{}
+}
+
+var _ Observer = &SystemdNotifier{}
+
+// NewSystemdNotifier creates a systemd notifier that reports the status of the given
+// health instance to the service manager.
+func NewSystemdNotifier(health *Health, configs ...SystemdConfigFunc) *SystemdNotifier {
+	n := &SystemdNotifier{
+		health:           health,
+		socket:           os.Getenv("NOTIFY_SOCKET"),
+		watchdogInterval: systemdWatchdogInterval(),
+		logger:           NilLogger,
+		clock:            defaultClock,
+		stop:             make(chan struct{}),
+	}
+
+	for _, f := range configs {
+		f(n)
+	}
+
+	return n
+}
+
+// systemdWatchdogInterval returns the watchdog interval requested by the service
+// manager, or zero if the watchdog is disabled or intended for another process.
+func systemdWatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+func (n *SystemdNotifier) OnPriorityStarted(priority int) {
+	n.notify(fmt.Sprintf("STATUS=Starting processes at priority %d", priority))
+}
+
+func (n *SystemdNotifier) OnPriorityHealthy(priority int) {
+	n.notify(fmt.Sprintf("STATUS=Processes at priority %d are healthy", priority))
+}
+
+func (n *SystemdNotifier) OnApplicationReady() {
+	n.notify("READY=1", "STATUS=Running")
+
+	if n.socket != "" && n.watchdogInterval > 0 {
+		go n.watchdog()
+	}
+}
+
+func (n *SystemdNotifier) OnShutdownBegin(reason error) {
+	if reason != nil {
+		n.notify("STOPPING=1", fmt.Sprintf("STATUS=Shutting down: %s", reason))
+	} else {
+		n.notify("STOPPING=1", "STATUS=Shutting down")
+	}
+}
+
+func (n *SystemdNotifier) OnShutdownComplete(errors []error) {
+	n.stopOnce.Do(func() { close(n.stop) })
+	n.notify("STATUS=Stopped")
+}
+
+// watchdog sends a keep-alive ping to the service manager at half the watchdog interval
+// while the health instance is healthy.
+func (n *SystemdNotifier) watchdog() {
+	ticker := n.clock.NewTicker(n.watchdogInterval / 2)
+	defer ticker.Stop()
+
+	for {
+		if n.health.Healthy() {
+			n.notify("WATCHDOG=1")
+		}
+
+		select {
+		case <-ticker.Chan():
+		case <-n.stop:
+			return
+		}
+	}
+}
+
+// notify sends the given newline-separated assignments to the service manager as a
+// single datagram. Failures are logged but otherwise ignored.
+func (n *SystemdNotifier) notify(assignments ...string) {
+	if n.socket == "" {
+		return
+	}
+
+	if err := sendSystemdNotification(n.socket, strings.Join(assignments, "\n")); err != nil {
+		n.logger.Warning("Failed to notify systemd: %s", err)
+	}
+}
+
+// sendSystemdNotification writes the given message to the unix datagram socket at the
+// given path. Paths beginning with @ refer to the abstract namespace.
+func sendSystemdNotification(socket, message string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(message))
+	return err
+}
@@ -0,0 +1,27 @@
+package process
+
+import (
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+type SystemdConfigFunc func(*SystemdNotifier)
+
+// WithSystemdLogger configures a systemd notifier to log failed notifications to the
+// given logger.
+func WithSystemdLogger(logger Logger) SystemdConfigFunc {
+	return func(n *SystemdNotifier) { n.logger = logger }
+}
+
+func withSystemdClock(clock glock.Clock) SystemdConfigFunc {
+	return func(n *SystemdNotifier) { n.clock = clock }
+}
+
+func withSystemdSocket(socket string) SystemdConfigFunc {
+	return func(n *SystemdNotifier) { n.socket = socket }
+}
+
+func withSystemdWatchdogInterval(interval time.Duration) SystemdConfigFunc {
+	return func(n *SystemdNotifier) { n.watchdogInterval = interval }
+}
@@ -0,0 +1,68 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdNotifier(t *testing.T) {
+	dir, err := os.MkdirTemp("", "systemd")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.Nil(t, err)
+	defer conn.Close()
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		require.Nil(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, err := conn.Read(buf)
+		require.Nil(t, err)
+		return string(buf[:n])
+	}
+
+	health := NewHealth()
+	clock := glock.NewMockClock()
+	notifier := NewSystemdNotifier(health, withSystemdSocket(socket), withSystemdWatchdogInterval(time.Second*10), withSystemdClock(clock))
+
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, _ := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaPriority(1))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health), WithObserver(notifier))
+	assert.Equal(t, "STATUS=Starting processes at priority 1", read())
+	assert.Equal(t, "STATUS=Processes at priority 1 are healthy", read())
+	assert.Equal(t, "READY=1\nSTATUS=Running", read())
+	assert.Equal(t, "WATCHDOG=1", read())
+
+	clock.BlockingAdvance(time.Second * 5)
+	assert.Equal(t, "WATCHDOG=1", read())
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+	assert.Equal(t, "STOPPING=1\nSTATUS=Shutting down", read())
+	assert.Equal(t, "STATUS=Stopped", read())
+}
+
+func TestSystemdNotifierWithoutSocket(t *testing.T) {
+	notifier := NewSystemdNotifier(NewHealth(), withSystemdSocket(""), withSystemdWatchdogInterval(time.Second))
+
+	notifier.OnApplicationReady()
+	notifier.OnShutdownBegin(nil)
+	notifier.OnShutdownComplete(nil)
+}
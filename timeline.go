@@ -0,0 +1,172 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// timeline records the start and end of the lifecycle phases of each process so
+// they can be rendered in the Chrome trace-event format.
+type timeline struct {
+	mu     sync.Mutex
+	events []timelineEvent
+	lanes  map[timelineLane]string
+}
+
+// timelineLane identifies a row of the timeline. Each priority is rendered as a
+// separate group of rows, and each process as a row within its priority. Events
+// belonging to the priority as a whole are rendered on the first row.
+type timelineLane struct {
+	priorityIndex int
+	index         int
+}
+
+type timelineEvent struct {
+	name  string
+	lane  timelineLane
+	start time.Time
+	end   time.Time
+	args  map[string]interface{}
+}
+
+func newTimeline() *timeline {
+	return &timeline{
+		lanes: map[timelineLane]string{},
+	}
+}
+
+// addLane names the given row of the timeline.
+func (t *timeline) addLane(lane timelineLane, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lanes[lane] = name
+}
+
+// record adds an event spanning the given times to the given row of the timeline.
+func (t *timeline) record(lane timelineLane, name string, start, end time.Time, err error) {
+	args := map[string]interface{}{}
+	if err != nil {
+		args["error"] = err.Error()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, timelineEvent{name: name, lane: lane, start: start, end: end, args: args})
+}
+
+// chromeTraceEvent is a single event in the Chrome trace-event format.
+type chromeTraceEvent struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"cat,omitempty"`
+	Phase     string                 `json:"ph"`
+	Timestamp float64                `json:"ts"`
+	Duration  float64                `json:"dur,omitempty"`
+	PID       int                    `json:"pid"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+// write serializes the timeline to the given writer in the Chrome trace-event format.
+// Timestamps are relative to the earliest recorded event.
+func (t *timeline) write(w io.Writer) error {
+	t.mu.Lock()
+	events := make([]timelineEvent, len(t.events))
+	copy(events, t.events)
+	lanes := make(map[timelineLane]string, len(t.lanes))
+	for lane, name := range t.lanes {
+		lanes[lane] = name
+	}
+	t.mu.Unlock()
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].start.Before(events[j].start) })
+
+	var origin time.Time
+	if len(events) > 0 {
+		origin = events[0].start
+	}
+
+	traceEvents := make([]chromeTraceEvent, 0, len(lanes)+len(events))
+	for lane, name := range lanes {
+		if lane.index == 0 {
+			traceEvents = append(traceEvents,
+				metadataEvent("process_name", lane, "name", name),
+				metadataEvent("process_sort_index", lane, "sort_index", lane.priorityIndex),
+			)
+		}
+
+		traceEvents = append(traceEvents,
+			metadataEvent("thread_name", lane, "name", name),
+			metadataEvent("thread_sort_index", lane, "sort_index", lane.index),
+		)
+	}
+	sort.SliceStable(traceEvents, func(i, j int) bool {
+		if traceEvents[i].PID != traceEvents[j].PID {
+			return traceEvents[i].PID < traceEvents[j].PID
+		}
+
+		return traceEvents[i].TID < traceEvents[j].TID
+	})
+
+	for _, event := range events {
+		traceEvents = append(traceEvents, chromeTraceEvent{
+			Name:      event.name,
+			Category:  "process",
+			Phase:     "X",
+			Timestamp: microseconds(event.start.Sub(origin)),
+			Duration:  microseconds(event.end.Sub(event.start)),
+			PID:       event.lane.priorityIndex,
+			TID:       event.lane.index,
+			Args:      event.args,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string             `json:"displayTimeUnit"`
+	}{
+		TraceEvents:     traceEvents,
+		DisplayTimeUnit: "ms",
+	})
+}
+
+// metadataEvent creates a Chrome trace metadata event for the given row of the timeline.
+func metadataEvent(name string, lane timelineLane, key string, value interface{}) chromeTraceEvent {
+	return chromeTraceEvent{
+		Name:  name,
+		Phase: "M",
+		PID:   lane.priorityIndex,
+		TID:   lane.index,
+		Args:  map[string]interface{}{key: value},
+	}
+}
+
+// microseconds returns the given duration as a fractional number of microseconds.
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// priorityLaneName returns the display name of the rows of the given priority.
+func priorityLaneName(priority int) string {
+	return fmt.Sprintf("priority %d", priority)
+}
+
+// recordTimeline adds an event spanning the given times to the process's row of the
+// timeline. This method no-ops if the meta instance is not being run by a process runner.
+func (m *Meta) recordTimeline(name string, start, end time.Time, err error) {
+	if m.timeline != nil {
+		m.timeline.record(m.timelineLane, name, start, end, err)
+	}
+}
+
+// WriteTimeline writes the start and end of every lifecycle phase of each process, as
+// well as the time spent waiting for each priority to become healthy, to the given writer
+// in the Chrome trace-event format. The output can be viewed in Perfetto or chrome://tracing.
+func (s *State) WriteTimeline(w io.Writer) error {
+	return s.timeline.write(w)
+}
@@ -0,0 +1,49 @@
+package process
+
+import "context"
+
+// Tracer creates spans around the lifecycle phases of processes.
+type Tracer interface {
+	// StartSpan begins a span with the given name and attributes. The returned
+	// context carries the new span and is passed to the hook being traced.
+	StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span)
+}
+
+// Span is an in-progress span created by a Tracer.
+type Span interface {
+	// End completes the span. A non-nil error value indicates that the traced
+	// phase failed.
+	End(err error)
+}
+
+type nilTracer struct{}
+type nilSpan struct{}
+
+var NilTracer Tracer = nilTracer{}
+
+func (t nilTracer) StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span) {
+	return ctx, nilSpan{}
+}
+
+func (s nilSpan) End(err error) {}
+
+// startSpan begins a span for the given lifecycle phase of this process.
+func (m *Meta) startSpan(ctx context.Context, phase string) (context.Context, Span) {
+	attributes := make(map[string]interface{}, len(m.options.metadata)+2)
+	for key, value := range m.options.metadata {
+		attributes[key] = value
+	}
+	attributes["process.name"] = m.Name()
+	attributes["process.phase"] = phase
+
+	return m.tracer.StartSpan(ctx, phase, attributes)
+}
+
+// traced invokes the given function within a span for the given lifecycle phase of
+// this process.
+func (m *Meta) traced(ctx context.Context, phase string, fn func(ctx context.Context) error) error {
+	ctx, span := m.startSpan(ctx, phase)
+	err := fn(ctx)
+	span.End(err)
+	return err
+}
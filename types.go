@@ -36,6 +36,20 @@ type Finalizer interface {
 	Finalize(ctx context.Context) error
 }
 
+// HealthChecker wraps a process with a way to report its current health.
+type HealthChecker interface {
+	// CheckHealth is the hook invoked periodically while the process is running.
+	// A nil error value indicates that the process is healthy.
+	CheckHealth(ctx context.Context) error
+}
+
+// Reloader wraps a process with a way to reload its configuration without restarting.
+type Reloader interface {
+	// Reload is the hook invoked on an initialized process when the application is
+	// asked to reload (e.g., on SIGHUP).
+	Reload(ctx context.Context) error
+}
+
 // InjecterFunc is a function conforming to the Injecter interface.
 type InjecterFunc func(ctx context.Context, meta *Meta) error
 
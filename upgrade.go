@@ -0,0 +1,361 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+)
+
+// The environment variables used to pass inherited listeners and the readiness pipe
+// from a process to the new process started during an upgrade.
+const (
+	upgradeListenFDsEnv     = "PROCESS_UPGRADE_LISTEN_FDS"
+	upgradeListenFDNamesEnv = "PROCESS_UPGRADE_LISTEN_FDNAMES"
+	upgradeReadyFDEnv       = "PROCESS_UPGRADE_READY_FD"
+	upgradePIDFileFDsEnv    = "PROCESS_UPGRADE_PIDFILE_FDS"
+)
+
+// defaultUpgradeReadyTimeout is the time the program started by an upgrade is given to
+// report readiness if the machine does not configure an explicit timeout.
+const defaultUpgradeReadyTimeout = time.Minute
+
+// upgrader starts a new copy of the program that inherits the listeners of the current
+// program.
+type upgrader struct {
+	mu           sync.Mutex
+	path         string
+	args         []string
+	stdout       io.Writer
+	stderr       io.Writer
+	readyTimeout time.Duration
+	notifySocket string
+	clock        glock.Clock
+	inProgress   bool
+}
+
+func newUpgrader() *upgrader {
+	return &upgrader{
+		args:         os.Args[1:],
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+		readyTimeout: defaultUpgradeReadyTimeout,
+		notifySocket: os.Getenv("NOTIFY_SOCKET"),
+		clock:        defaultClock,
+	}
+}
+
+// Upgrade starts a new copy of the program that inherits every listener in the listener
+// registry (see WithListeners). The new program receives the listeners through the
+// environment and should load them with NewListenersFromEnvironment. Once the processes
+// registered to every priority of the new program are healthy, the new program reports
+// readiness and the processes of this program are signalled to exit.
+//
+// The locks on the PID files registered to the container (see PIDFile) are inherited by
+// the new program, which adopts them when its own PID file initializers run.
+//
+// If $NOTIFY_SOCKET is set, the PID of the new program is sent to the service manager as
+// MAINPID= once it reports readiness so that the service is not considered stopped when
+// this program exits. As the new program notifies the service manager before it becomes
+// the main process of the service, systemd units must set NotifyAccess=all.
+//
+// An error is returned if the new program cannot be started, exits before reporting
+// readiness, does not report readiness within the timeout configured by
+// WithUpgradeReadyTimeout, or if the given context is canceled first. In any of these cases the new
+// program is killed and this program continues to run.
+func (s *State) Upgrade(ctx context.Context) error {
+	var pidFiles []*PIDFile
+	if s.container != nil {
+		for _, meta := range s.container.Meta() {
+			if pidFile, ok := meta.wrapped.(*PIDFile); ok {
+				pidFiles = append(pidFiles, pidFile)
+			}
+		}
+	}
+
+	if err := s.upgrader.upgrade(ctx, s.listeners, pidFiles); err != nil {
+		return err
+	}
+
+	s.Shutdown(ctx)
+	return nil
+}
+
+// upgrade starts a new copy of the program with the given listeners and the locks of the
+// given PID files and blocks until it reports readiness.
+func (u *upgrader) upgrade(ctx context.Context, listeners *Listeners, pidFiles []*PIDFile) error {
+	u.mu.Lock()
+	if u.inProgress {
+		u.mu.Unlock()
+		return ErrUpgradeInProgress
+	}
+	u.inProgress = true
+	u.mu.Unlock()
+
+	err := u.startAndWait(ctx, listeners, pidFiles)
+	if err != nil {
+		u.mu.Lock()
+		u.inProgress = false
+		u.mu.Unlock()
+		return err
+	}
+
+	for _, pidFile := range pidFiles {
+		pidFile.handOff()
+	}
+
+	return nil
+}
+
+func (u *upgrader) startAndWait(ctx context.Context, listeners *Listeners, pidFiles []*PIDFile) error {
+	path := u.path
+	if path == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+
+		path = executable
+	}
+
+	var names []string
+	var files []*os.File
+	if listeners != nil {
+		var err error
+		if names, files, err = listeners.files(); err != nil {
+			return err
+		}
+	}
+	defer closeFiles(files)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	extraFiles := append(append([]*os.File(nil), files...), w)
+
+	var pidFileFDs []string
+	for _, pidFile := range pidFiles {
+		if file := pidFile.lockedFile(); file != nil {
+			pidFileFDs = append(pidFileFDs, strconv.Itoa(listenFDsStart+len(extraFiles)))
+			extraFiles = append(extraFiles, file)
+		}
+	}
+
+	cmd := exec.Command(path, u.args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = u.stdout
+	cmd.Stderr = u.stderr
+	cmd.ExtraFiles = extraFiles
+	cmd.Env = append(
+		upgradeEnviron(),
+		fmt.Sprintf("%s=%d", upgradeListenFDsEnv, len(files)),
+		fmt.Sprintf("%s=%s", upgradeListenFDNamesEnv, strings.Join(names, ":")),
+		fmt.Sprintf("%s=%d", upgradeReadyFDEnv, listenFDsStart+len(files)),
+		fmt.Sprintf("%s=%s", upgradePIDFileFDsEnv, strings.Join(pidFileFDs, ",")),
+	)
+
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return err
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		if err == io.EOF {
+			err = ErrUpgradeChildExited
+		}
+
+		ready <- err
+	}()
+
+	select {
+	case err = <-ready:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-afterZeroUnbounded(u.clock, u.readyTimeout):
+		err = ErrUpgradeReadyTimeout
+	}
+
+	if err == nil {
+		err = u.notifyMainPID(cmd.Process.Pid)
+	}
+
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// notifyMainPID reports the given PID as the main process of the service to the service
+// manager. This method no-ops if $NOTIFY_SOCKET was not set.
+func (u *upgrader) notifyMainPID(pid int) error {
+	if u.notifySocket == "" {
+		return nil
+	}
+
+	return sendSystemdNotification(u.notifySocket, fmt.Sprintf("MAINPID=%d", pid))
+}
+
+// upgradeEnviron returns the environment of the current program without the variables
+// describing inherited listeners.
+func upgradeEnviron() []string {
+	var environ []string
+	for _, value := range os.Environ() {
+		switch strings.SplitN(value, "=", 2)[0] {
+		case "LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES", upgradeListenFDsEnv, upgradeListenFDNamesEnv, upgradeReadyFDEnv, upgradePIDFileFDsEnv:
+			continue
+		}
+
+		environ = append(environ, value)
+	}
+
+	return environ
+}
+
+// newListenersFromUpgrade creates a listener registry populated with the listeners passed
+// to this program by the program it is replacing. If the program was not started by an
+// upgrade, this function returns false.
+func newListenersFromUpgrade() (*Listeners, bool, error) {
+	value, ok := os.LookupEnv(upgradeListenFDsEnv)
+	if !ok {
+		return nil, false, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, true, fmt.Errorf("malformed %s: %w", upgradeListenFDsEnv, err)
+	}
+	readyFD, err := strconv.Atoi(os.Getenv(upgradeReadyFDEnv))
+	if err != nil {
+		return nil, true, fmt.Errorf("malformed %s: %w", upgradeReadyFDEnv, err)
+	}
+
+	names := strings.Split(os.Getenv(upgradeListenFDNamesEnv), ":")
+	if len(names) != n && n > 0 {
+		return nil, true, fmt.Errorf("malformed %s: expected %d names", upgradeListenFDNamesEnv, n)
+	}
+
+	files := make([]*os.File, 0, n)
+	for i := 0; i < n; i++ {
+		files = append(files, os.NewFile(uintptr(listenFDsStart+i), names[i]))
+	}
+
+	listeners, err := newListenersFromFiles(files)
+	if err != nil {
+		return nil, true, err
+	}
+
+	listeners.ready = os.NewFile(uintptr(readyFD), "upgrade-ready")
+	return listeners, true, nil
+}
+
+// inheritedPIDFiles holds the locked PID files passed to this program by the program it
+// is replacing that have not yet been adopted by a PID file initializer.
+var inheritedPIDFiles struct {
+	once  sync.Once
+	mu    sync.Mutex
+	files []*os.File
+}
+
+// inheritedPIDFile returns the locked PID file inherited from the program replaced by an
+// upgrade that is the file currently at the given path. If there is no such file, this
+// function returns false. Each inherited file is returned at most once.
+func inheritedPIDFile(path string) (*os.File, bool) {
+	inheritedPIDFiles.once.Do(func() {
+		for _, value := range strings.Split(os.Getenv(upgradePIDFileFDsEnv), ",") {
+			if fd, err := strconv.Atoi(value); err == nil {
+				inheritedPIDFiles.files = append(inheritedPIDFiles.files, os.NewFile(uintptr(fd), "upgrade-pidfile"))
+			}
+		}
+
+		os.Unsetenv(upgradePIDFileFDsEnv)
+	})
+
+	inheritedPIDFiles.mu.Lock()
+	defer inheritedPIDFiles.mu.Unlock()
+
+	for i, file := range inheritedPIDFiles.files {
+		if same, err := isFileAtPath(file, path); err == nil && same {
+			inheritedPIDFiles.files = append(inheritedPIDFiles.files[:i:i], inheritedPIDFiles.files[i+1:]...)
+			return file, true
+		}
+	}
+
+	return nil, false
+}
+
+// files returns the names of the registered listeners and packet connections in
+// lexicographic order along with a duplicate of the file underlying each of them.
+func (l *Listeners) files() ([]string, []*os.File, error) {
+	names := append(l.Names(), l.PacketConnNames()...)
+	sort.Strings(names)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files := make([]*os.File, 0, len(names))
+	for _, name := range names {
+		var value interface{} = l.listeners[name]
+		if conn, ok := l.packetConns[name]; ok {
+			value = conn
+		}
+
+		filer, ok := value.(interface{ File() (*os.File, error) })
+		if !ok {
+			closeFiles(files)
+			return nil, nil, fmt.Errorf("listener %q: %w", name, ErrListenerNotInheritable)
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			closeFiles(files)
+			return nil, nil, fmt.Errorf("listener %q: %w", name, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return names, files, nil
+}
+
+// notifyReady reports to the program that started this program via an upgrade that this
+// program is ready. This method no-ops if the program was not started by an upgrade or if
+// readiness was already reported.
+func (l *Listeners) notifyReady() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ready == nil {
+		return nil
+	}
+
+	defer func() { l.ready = nil }()
+	defer l.ready.Close()
+
+	_, err := l.ready.Write([]byte{1})
+	return err
+}
+
+// closeFiles closes each of the given files.
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
@@ -0,0 +1,180 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package process
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeTestChildEnv = "PROCESS_UPGRADE_TEST_CHILD"
+
+func TestUpgrade(t *testing.T) {
+	listeners := NewListeners()
+	defer listeners.Close()
+
+	listener, err := listeners.Listen("http", "tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	dir, err := os.MkdirTemp("", "upgrade")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "notify")
+	notifications, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.Nil(t, err)
+	defer notifications.Close()
+
+	os.Setenv(upgradeTestChildEnv, "1")
+	defer os.Unsetenv(upgradeTestChildEnv)
+
+	state := Run(
+		context.Background(),
+		builder.Build(),
+		WithListeners(listeners),
+		WithUpgradeExecutable(os.Args[0], "-test.run=^TestUpgradeChild$"),
+		withUpgradeOutput(io.Discard),
+		withUpgradeNotifySocket(socket),
+	)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	require.Nil(t, state.Upgrade(ctx))
+	assert.Equal(t, ErrUpgradeInProgress, state.Upgrade(ctx))
+	require.True(t, state.Wait(context.Background()))
+	require.Nil(t, listeners.Close())
+
+	// The service manager is told that the upgraded program is the main process
+	buf := make([]byte, 1024)
+	require.Nil(t, notifications.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := notifications.Read(buf)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(buf[:n]), "MAINPID="))
+	pid, err := strconv.Atoi(strings.TrimPrefix(string(buf[:n]), "MAINPID="))
+	require.Nil(t, err)
+	assert.NotEqual(t, os.Getpid(), pid)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.Nil(t, err)
+	assert.Equal(t, "child\n", line)
+}
+
+func TestUpgradeChildExited(t *testing.T) {
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	state := Run(
+		context.Background(),
+		builder.Build(),
+		WithUpgradeExecutable(os.Args[0], "-test.run=^$"),
+		withUpgradeOutput(io.Discard),
+	)
+	<-started
+
+	assert.Equal(t, ErrUpgradeChildExited, state.Upgrade(context.Background()))
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+}
+
+func TestUpgradeReadyTimeout(t *testing.T) {
+	builder := NewContainerBuilder()
+	process := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	process.RunFunc.SetDefaultHook(runHook)
+	builder.RegisterProcess(process, WithMetaName("p"))
+
+	os.Setenv(upgradeTestHangEnv, "1")
+	defer os.Unsetenv(upgradeTestHangEnv)
+
+	clock := glock.NewMockClock()
+	state := Run(
+		context.Background(),
+		builder.Build(),
+		WithUpgradeExecutable(os.Args[0], "-test.run=^TestUpgradeChildHang$"),
+		WithUpgradeReadyTimeout(time.Second*5),
+		withUpgradeOutput(io.Discard),
+		withUpgradeClock(clock),
+	)
+	<-started
+
+	// A failed upgrade does not prevent later upgrades
+	for i := 0; i < 2; i++ {
+		result := make(chan error, 1)
+		go func() { result <- state.Upgrade(context.Background()) }()
+
+		clock.BlockingAdvance(time.Second * 5)
+		assert.Equal(t, ErrUpgradeReadyTimeout, <-result)
+	}
+
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+}
+
+const upgradeTestHangEnv = "PROCESS_UPGRADE_TEST_HANG"
+
+// TestUpgradeChildHang is the program started by TestUpgradeReadyTimeout. It never
+// reports readiness.
+func TestUpgradeChildHang(t *testing.T) {
+	if os.Getenv(upgradeTestHangEnv) == "" {
+		t.Skip("only run as the program started by TestUpgradeReadyTimeout")
+	}
+
+	time.Sleep(time.Minute)
+}
+
+// TestUpgradeChild is the program started by TestUpgrade. It serves a single connection
+// on the inherited listener and exits.
+func TestUpgradeChild(t *testing.T) {
+	if os.Getenv(upgradeTestChildEnv) == "" {
+		t.Skip("only run as the program started by TestUpgrade")
+	}
+
+	listeners, err := NewListenersFromEnvironment()
+	require.Nil(t, err)
+
+	listener, ok := listeners.Get("http")
+	require.True(t, ok)
+
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(RunnerFunc(func(ctx context.Context) error {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("child\n"))
+		return err
+	}), WithMetaName("child"), WithEarlyExit(true))
+
+	state := Run(context.Background(), builder.Build(), WithListeners(listeners))
+	require.True(t, state.Wait(context.Background()))
+}
@@ -0,0 +1,157 @@
+package process
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// BuildE creates a frozen and immutable version of the container as Build does, but
+// returns an error describing every problem with the registered processes instead of
+// accepting them silently. See Validate.
+func (b *ContainerBuilder) BuildE(configs ...MetaConfigFunc) (*Container, error) {
+	container := b.Build(configs...)
+	if err := b.validate(container); err != nil {
+		return nil, err
+	}
+
+	return container, nil
+}
+
+// Validate returns an error describing every problem with the processes registered to
+// the container builder thus far. A process is invalid if its name is used by another
+// process, if it implements none of Initializer, Runner, Stopper, or Finalizer, if it
+// implements Stopper but not Runner, if it is configured with a negative timeout, or if
+// it implements HealthChecker and is configured with a health check failure or success
+// threshold less than one. If more than one problem exists, a MultiError is returned.
+func (b *ContainerBuilder) Validate(configs ...MetaConfigFunc) error {
+	return b.validate(b.Build(configs...))
+}
+
+// hookMethods maps the name of each hook method to the interface declaring it.
+var hookMethods = []struct {
+	name  string
+	iface reflect.Type
+}{
+	{"Init", reflect.TypeOf((*Initializer)(nil)).Elem()},
+	{"Run", reflect.TypeOf((*Runner)(nil)).Elem()},
+	{"Stop", reflect.TypeOf((*Stopper)(nil)).Elem()},
+	{"Finalize", reflect.TypeOf((*Finalizer)(nil)).Elem()},
+	{"CheckHealth", reflect.TypeOf((*HealthChecker)(nil)).Elem()},
+	{"Reload", reflect.TypeOf((*Reloader)(nil)).Elem()},
+}
+
+func (b *ContainerBuilder) validate(container *Container) error {
+	var errs []error
+	names := map[string]struct{}{}
+
+	for _, priority := range container.priorities {
+		for _, meta := range container.meta[priority] {
+			if name := meta.options.name; name != "" {
+				if _, ok := names[name]; ok {
+					errs = append(errs, fmt.Errorf("%s: name is used by another process", name))
+				}
+				names[name] = struct{}{}
+			}
+
+			for _, problem := range validateMeta(meta, b.strict) {
+				errs = append(errs, fmt.Errorf("%s: %s", meta.Name(), problem))
+			}
+		}
+	}
+
+	return errorOrNil(errs)
+}
+
+// validateMeta returns a description of each problem with the given process.
+func validateMeta(meta *Meta, strict bool) []string {
+	var problems []string
+
+	_, isInitializer := meta.wrapped.(Initializer)
+	_, isRunner := meta.wrapped.(Runner)
+	_, isStopper := meta.wrapped.(Stopper)
+	_, isFinalizer := meta.wrapped.(Finalizer)
+
+	if !isInitializer && !isRunner && !isStopper && !isFinalizer {
+		problems = append(problems, "does not implement Initializer, Runner, Stopper, or Finalizer")
+	}
+	if isStopper && !isRunner {
+		problems = append(problems, "implements Stopper but not Runner")
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"init timeout", meta.options.initTimeout},
+		{"startup timeout", meta.options.startupTimeout},
+		{"stop timeout", meta.options.stopTimeout},
+		{"shutdown timeout", meta.options.shutdownTimeout},
+		{"finalize timeout", meta.options.finalizeTimeout},
+		{"health check timeout", meta.options.healthCheckTimeout},
+		{"watchdog timeout", meta.options.watchdogTimeout},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s is negative (%s)", d.name, d.value))
+		}
+	}
+
+	if _, ok := meta.healthChecker(); ok {
+		if meta.options.healthCheckFailureThreshold < 1 {
+			problems = append(problems, fmt.Sprintf("health check failure threshold is less than one (%d)", meta.options.healthCheckFailureThreshold))
+		}
+		if meta.options.healthCheckSuccessThreshold < 1 {
+			problems = append(problems, fmt.Sprintf("health check success threshold is less than one (%d)", meta.options.healthCheckSuccessThreshold))
+		}
+	}
+
+	if strict && meta.wrapped != nil {
+		problems = append(problems, validateHookSignatures(reflect.TypeOf(meta.wrapped))...)
+	}
+
+	return problems
+}
+
+// validateHookSignatures returns a description of each method of the given type that is
+// named like a hook method but does not satisfy the hook's interface.
+func validateHookSignatures(t reflect.Type) []string {
+	var problems []string
+	for _, hook := range hookMethods {
+		if t.Implements(hook.iface) {
+			continue
+		}
+
+		expected, _ := hook.iface.MethodByName(hook.name)
+
+		if method, ok := t.MethodByName(hook.name); ok {
+			problems = append(problems, fmt.Sprintf("method %s has signature %s, expected %s", hook.name, describeMethod(method.Type, true), describeMethod(expected.Type, false)))
+			continue
+		}
+
+		if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(hook.iface) {
+			problems = append(problems, fmt.Sprintf("method %s is declared on a pointer receiver but the process was registered by value", hook.name))
+		}
+	}
+
+	return problems
+}
+
+// describeMethod returns the signature of the given method type as a function literal
+// type. If hasReceiver is true, the first parameter of the given type is omitted.
+func describeMethod(t reflect.Type, hasReceiver bool) string {
+	in := make([]reflect.Type, 0, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		if i == 0 && hasReceiver {
+			continue
+		}
+		in = append(in, t.In(i))
+	}
+
+	out := make([]reflect.Type, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, t.Out(i))
+	}
+
+	return reflect.FuncOf(in, out, t.IsVariadic()).String()
+}
@@ -0,0 +1,81 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stopOnlyProcess struct{}
+
+func (stopOnlyProcess) Stop(ctx context.Context) error { return nil }
+
+type nearMissProcess struct{}
+
+func (nearMissProcess) Run(ctx context.Context) error { return nil }
+func (nearMissProcess) Stop() error                   { return nil }
+
+type pointerReceiverProcess struct{}
+
+func (*pointerReceiverProcess) Init(ctx context.Context) error { return nil }
+
+func TestBuildE(t *testing.T) {
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("a"))
+	builder.RegisterInitializer(NewMockMaximumProcess(), WithMetaName("b"), WithMetaPriority(1))
+
+	container, err := builder.BuildE()
+	require.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, container.Priorities())
+}
+
+func TestBuildEInvalid(t *testing.T) {
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("a"))
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("a"), WithMetaPriority(1))
+	builder.RegisterProcess(struct{}{}, WithMetaName("b"))
+	builder.RegisterProcess(stopOnlyProcess{}, WithMetaName("c"))
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("d"), WithMetaStopTimeout(-time.Second))
+	builder.RegisterProcess(nearMissProcess{}, WithMetaName("e"))
+	builder.RegisterProcess(newHealthCheckingProcess(), WithMetaName("f"), WithMetaHealthCheckInterval(0), WithMetaHealthCheckFailureThreshold(0))
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("g"), WithMetaHealthCheckSuccessThreshold(0))
+
+	container, err := builder.BuildE()
+	assert.Nil(t, container)
+	require.IsType(t, MultiError{}, err)
+	assert.Equal(t, []string{
+		"b: does not implement Initializer, Runner, Stopper, or Finalizer",
+		"c: implements Stopper but not Runner",
+		"d: stop timeout is negative (-1s)",
+		"f: health check failure threshold is less than one (0)",
+		"a: name is used by another process",
+	}, errorStrings(err.(MultiError)))
+}
+
+func TestValidateStrict(t *testing.T) {
+	builder := NewContainerBuilder(WithStrictValidation())
+	builder.RegisterProcess(NewMockMaximumProcess(), WithMetaName("a"))
+	builder.RegisterProcess(nearMissProcess{}, WithMetaName("b"))
+	builder.RegisterProcess(pointerReceiverProcess{}, WithMetaName("c"))
+	builder.RegisterProcess(&pointerReceiverProcess{}, WithMetaName("d"))
+
+	err := builder.Validate()
+	require.IsType(t, MultiError{}, err)
+	assert.Equal(t, []string{
+		"b: method Stop has signature func() error, expected func(context.Context) error",
+		"c: does not implement Initializer, Runner, Stopper, or Finalizer",
+		"c: method Init is declared on a pointer receiver but the process was registered by value",
+	}, errorStrings(err.(MultiError)))
+}
+
+func errorStrings(errs []error) []string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
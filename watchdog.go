@@ -0,0 +1,109 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// WatchdogError occurs when the health components associated with a process stay
+// unhealthy for longer than the configured watchdog timeout after the process has
+// become healthy.
+type WatchdogError struct {
+	// MetaName is the name of the stalled process.
+	MetaName string
+
+	// Timeout is the configured watchdog timeout.
+	Timeout time.Duration
+
+	// Restarted is true if the process was restarted in response to this error.
+	// Restarted processes do not cause the process runner to shut down.
+	Restarted bool
+
+	// Dump holds the stacks of all goroutines at the moment the watchdog fired.
+	Dump []byte
+}
+
+func (e *WatchdogError) Error() string {
+	return fmt.Sprintf("%s: %s for %s", e.MetaName, ErrWatchdogTimeout, e.Timeout)
+}
+
+func (e *WatchdogError) Unwrap() error {
+	return ErrWatchdogTimeout
+}
+
+// watchWatchdog returns a channel that will receive a watchdog error once the health
+// components of this process have remained unhealthy for the configured watchdog
+// timeout. If no watchdog timeout is configured then a nil channel is returned.
+// Note that reading from a nil channel blocks forever.
+func (m *Meta) watchWatchdog(ctx context.Context) (<-chan *WatchdogError, error) {
+	if m.options.watchdogTimeout == 0 {
+		return nil, nil
+	}
+
+	components, err := m.healthComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return nil, nil
+	}
+
+	stalled := make(chan *WatchdogError, 1)
+
+	go func() {
+		ch, cancel := m.subscribeHealthChanges()
+		defer cancel()
+
+		var timeout <-chan time.Time
+		for {
+			if healthy(components) {
+				timeout = nil
+			} else if timeout == nil {
+				timeout = m.options.watchdogClock.After(m.options.watchdogTimeout)
+			}
+
+			select {
+			case <-ch:
+			case <-timeout:
+				stalled <- &WatchdogError{
+					MetaName: m.Name(),
+					Timeout:  m.options.watchdogTimeout,
+					Dump:     goroutineDump(),
+				}
+				return
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return stalled, nil
+}
+
+// healthy returns true if all of the given components are healthy.
+func healthy(components []*HealthComponentStatus) bool {
+	for _, component := range components {
+		if !component.Healthy() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// goroutineDump returns the formatted stack traces of all current goroutines.
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+
+		buf = make([]byte, len(buf)*2)
+	}
+}
@@ -0,0 +1,222 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaWatchdogTimeout(t *testing.T) {
+	health := NewHealth()
+	healthComponent, _ := health.Register("test")
+
+	clock := glock.NewMockClock()
+	wrapped := NewMockMaximumProcess()
+	runHook, started := newSingalingSingleErrorFunc()
+	wrapped.RunFunc.SetDefaultHook(runHook)
+	meta := newMeta(wrapped, WithMetaName("test-service"), WithMetaHealth(health), WithMetaHealthKey("test"), WithMetaWatchdogTimeout(time.Second*5), withMetaWatchdogClock(clock))
+
+	require.Nil(t, meta.Init(context.Background()))
+	results := runAsync(context.Background(), meta.Run)
+
+	<-started
+	var err error
+	stallUntil(t, healthComponent, clock, func() bool {
+		select {
+		case err = <-results:
+			return true
+		case <-time.After(time.Millisecond * 100):
+			return false
+		}
+	})
+
+	assert.True(t, errors.Is(err, ErrWatchdogTimeout))
+	assert.EqualError(t, err, "test-service: process stalled; watchdog timeout elapsed for 5s")
+
+	var watchdogErr *WatchdogError
+	require.True(t, errors.As(err, &watchdogErr))
+	assert.False(t, watchdogErr.Restarted)
+	assert.Contains(t, string(watchdogErr.Dump), "goroutine")
+}
+
+func TestMetaWatchdogRestart(t *testing.T) {
+	health := NewHealth()
+	healthComponent, _ := health.Register("test")
+
+	clock := glock.NewMockClock()
+	wrapped := NewMockMaximumProcess()
+	started := make(chan struct{}, 2)
+	wrapped.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	reported := make(chan error, 1)
+	meta := newMeta(wrapped, WithMetaName("test-service"), WithMetaHealth(health), WithMetaHealthKey("test"), WithMetaWatchdogTimeout(time.Second*5), WithMetaWatchdogRestart(true), withMetaWatchdogClock(clock))
+	meta.errorReporter = func(err error) { reported <- err }
+
+	require.Nil(t, meta.Init(context.Background()))
+	results := runAsync(context.Background(), meta.Run)
+
+	<-started
+	var err error
+	stallUntil(t, healthComponent, clock, func() bool {
+		select {
+		case err = <-reported:
+			return true
+		case <-time.After(time.Millisecond * 100):
+			return false
+		}
+	})
+	assert.True(t, isRestartError(err))
+
+	<-started
+	require.Nil(t, meta.Stop(context.Background()))
+	assertChannelContents(t, readErrorChannel(results), seq(nil))
+}
+
+func TestMetaWatchdogTimeoutRunIgnoresContext(t *testing.T) {
+	health := NewHealth()
+	healthComponent, _ := health.Register("test")
+
+	clock := glock.NewMockClock()
+	wrapped := NewMockMaximumProcess()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	wrapped.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	reported := make(chan error, 1)
+	meta := newMeta(wrapped, WithMetaName("test-service"), WithMetaHealth(health), WithMetaHealthKey("test"), WithMetaWatchdogTimeout(time.Second*5), withMetaWatchdogClock(clock))
+	meta.errorReporter = func(err error) { reported <- err }
+
+	require.Nil(t, meta.Init(context.Background()))
+	results := runAsync(context.Background(), meta.Run)
+
+	<-started
+	var err error
+	stallUntil(t, healthComponent, clock, func() bool {
+		select {
+		case err = <-reported:
+			return true
+		case <-time.After(time.Millisecond * 100):
+			return false
+		}
+	})
+
+	// The error is reported while the wedged Run method is still blocked
+	assert.True(t, errors.Is(err, ErrWatchdogTimeout))
+	assert.False(t, isRestartError(err))
+
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Second * 5)
+
+		select {
+		case err := <-results:
+			assert.Nil(t, err)
+			return true
+		case <-time.After(time.Millisecond * 10):
+			return false
+		}
+	}, time.Second*5, time.Millisecond)
+}
+
+func TestMetaWatchdogRestartRunIgnoresContext(t *testing.T) {
+	health := NewHealth()
+	healthComponent, _ := health.Register("test")
+
+	clock := glock.NewMockClock()
+	wrapped := NewMockMaximumProcess()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	defer close(release)
+	wrapped.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	meta := newMeta(wrapped, WithMetaName("test-service"), WithMetaHealth(health), WithMetaHealthKey("test"), WithMetaWatchdogTimeout(time.Second*5), WithMetaWatchdogRestart(true), withMetaWatchdogClock(clock))
+
+	require.Nil(t, meta.Init(context.Background()))
+	results := runAsync(context.Background(), meta.Run)
+
+	<-started
+	var err error
+	stallUntil(t, healthComponent, clock, func() bool {
+		select {
+		case err = <-results:
+			return true
+		case <-time.After(time.Millisecond * 100):
+			return false
+		}
+	})
+
+	// The process is not restarted while the previous Run method is still blocked
+	var watchdogErr *WatchdogError
+	require.True(t, errors.As(err, &watchdogErr))
+	assert.False(t, watchdogErr.Restarted)
+	assert.Len(t, started, 0)
+}
+
+// stallUntil repeatedly flips the given health component from healthy to unhealthy and
+// advances the given clock past the watchdog timeout until the given condition holds. The
+// watchdog is armed only once the process has been observed healthy, which happens
+// asynchronously, so a single transition is not guaranteed to be seen.
+func stallUntil(t *testing.T, component *HealthComponentStatus, clock *glock.MockClock, condition func() bool) {
+	require.Eventually(t, func() bool {
+		component.Update(true)
+		time.Sleep(time.Millisecond * 10)
+		component.Update(false)
+		time.Sleep(time.Millisecond * 10)
+		clock.Advance(time.Second * 5)
+		return condition()
+	}, time.Second*5, time.Millisecond)
+}
+
+func TestRunWatchdogRestart(t *testing.T) {
+	health := NewHealth()
+	healthComponent, _ := health.Register("test")
+
+	clock := glock.NewMockClock()
+	process := NewMockMaximumProcess()
+	started := make(chan struct{}, 2)
+	process.RunFunc.SetDefaultHook(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	builder := NewContainerBuilder()
+	builder.RegisterProcess(process, WithMetaName("p"), WithMetaHealthKey("test"), WithMetaWatchdogTimeout(time.Second*5), WithMetaWatchdogRestart(true), withMetaWatchdogClock(clock))
+
+	state := Run(context.Background(), builder.Build(WithMetaHealth(health)), WithHealth(health))
+
+	<-started
+	stallUntil(t, healthComponent, clock, func() bool {
+		select {
+		case <-started:
+			return true
+		case <-time.After(time.Millisecond * 100):
+			return false
+		}
+	})
+
+	// A restart does not prevent a later clean exit
+	healthComponent.Update(true)
+	state.Shutdown(context.Background())
+	require.True(t, state.Wait(context.Background()))
+	assert.Empty(t, state.Errors())
+	require.Len(t, state.Restarts(), 1)
+	assert.True(t, isRestartError(state.Restarts()[0]))
+}